@@ -0,0 +1,169 @@
+package hdns
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by requests which are not sent because the
+// CircuitBreaker of the Client is open.
+var ErrCircuitOpen = errors.New("hdns: circuit breaker is open")
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+// States of a CircuitBreaker.
+const (
+	// CircuitClosed lets all requests pass.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails all requests with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a single request pass to probe whether the API
+	// works again.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerOpts specifies parameters for creating a CircuitBreaker.
+type CircuitBreakerOpts struct {
+	// Threshold is the number of consecutive failed requests which opens
+	// the circuit. Defaults to 5.
+	Threshold int
+	// Cooldown is the duration the circuit stays open before a request is
+	// let pass again. Defaults to 30 seconds.
+	Cooldown time.Duration
+	// OnStateChange, if set, is called whenever the state changes.
+	OnStateChange func(from, to CircuitState)
+}
+
+// CircuitBreaker stops sending requests to the API after consecutive
+// failures. Every request sent to the API counts, including retries: a
+// request fails if no response is received or the API responds with a 5xx
+// status code. Requests failing before they are sent, for example while
+// waiting for the RateLimiter, do not count. Once the circuit is open, requests fail with
+// ErrCircuitOpen until the cooldown has passed. Then a single request is
+// let pass: if it succeeds, the circuit is closed again, otherwise it
+// reopens. A CircuitBreaker is safe for concurrent use.
+type CircuitBreaker struct {
+	threshold     int
+	cooldown      time.Duration
+	onStateChange func(from, to CircuitState)
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker creates a new CircuitBreaker.
+func NewCircuitBreaker(opts CircuitBreakerOpts) *CircuitBreaker {
+	cb := &CircuitBreaker{
+		threshold:     opts.Threshold,
+		cooldown:      opts.Cooldown,
+		onStateChange: opts.OnStateChange,
+	}
+	if cb.threshold <= 0 {
+		cb.threshold = 5
+	}
+	if cb.cooldown <= 0 {
+		cb.cooldown = 30 * time.Second
+	}
+	return cb
+}
+
+// State returns the current state.
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// allow returns ErrCircuitOpen if a request must not be sent.
+func (cb *CircuitBreaker) allow(now time.Time) error {
+	cb.mu.Lock()
+	from := cb.state
+	switch cb.state {
+	case CircuitOpen:
+		if now.Sub(cb.openedAt) < cb.cooldown {
+			cb.mu.Unlock()
+			return ErrCircuitOpen
+		}
+		cb.state = CircuitHalfOpen
+		cb.probing = true
+	case CircuitHalfOpen:
+		if cb.probing {
+			cb.mu.Unlock()
+			return ErrCircuitOpen
+		}
+		cb.probing = true
+	}
+	to := cb.state
+	cb.mu.Unlock()
+
+	cb.notify(from, to)
+	return nil
+}
+
+// record records the outcome of a request let pass by allow.
+func (cb *CircuitBreaker) record(now time.Time, failed, ignored bool) {
+	cb.mu.Lock()
+	from := cb.state
+	if cb.state == CircuitHalfOpen {
+		cb.probing = false
+	}
+	switch {
+	case ignored:
+	case !failed:
+		cb.failures = 0
+		cb.state = CircuitClosed
+	default:
+		cb.failures++
+		if cb.state == CircuitHalfOpen || cb.failures >= cb.threshold {
+			cb.state = CircuitOpen
+			cb.openedAt = now
+		}
+	}
+	to := cb.state
+	cb.mu.Unlock()
+
+	cb.notify(from, to)
+}
+
+func (cb *CircuitBreaker) notify(from, to CircuitState) {
+	if from != to && cb.onStateChange != nil {
+		cb.onStateChange(from, to)
+	}
+}
+
+// send sends r with the Doer of the Client, guarded by the CircuitBreaker
+// of the Client, if any.
+func (c *Client) send(r *http.Request) (*http.Response, error) {
+	if c.circuitBreaker == nil {
+		return c.doer.Do(r)
+	}
+	if err := c.circuitBreaker.allow(time.Now()); err != nil {
+		return nil, err
+	}
+	resp, err := c.doer.Do(r)
+	// Requests canceled by the caller tell nothing about the API.
+	ignored := err != nil && errors.Is(r.Context().Err(), context.Canceled)
+	failed := err != nil || resp.StatusCode >= 500
+	c.circuitBreaker.record(time.Now(), failed, ignored)
+	return resp, err
+}
@@ -0,0 +1,110 @@
+package hdns
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	var mu sync.Mutex
+	var transitions []string
+	cb := NewCircuitBreaker(CircuitBreakerOpts{
+		Threshold: 2,
+		Cooldown:  50 * time.Millisecond,
+		OnStateChange: func(from, to CircuitState) {
+			mu.Lock()
+			transitions = append(transitions, from.String()+"->"+to.String())
+			mu.Unlock()
+		},
+	})
+	env := newTestEnv(WithCircuitBreaker(cb))
+	defer env.Teardown()
+
+	var calls, failing int32 = 0, 1
+	env.Mux.HandleFunc("/zones/1", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"zone":{"id":"1"}}`))
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := env.Client.Zone.GetByID(ctx, "1"); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("request %d: expected API error, got %v", i, err)
+		}
+	}
+	if s := cb.State(); s != CircuitOpen {
+		t.Fatalf("expected open circuit, got %s", s)
+	}
+	if _, _, err := env.Client.Zone.GetByID(ctx, "1"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Fatalf("expected 2 calls, got %d", n)
+	}
+
+	// A failing probe reopens the circuit.
+	time.Sleep(60 * time.Millisecond)
+	if _, _, err := env.Client.Zone.GetByID(ctx, "1"); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected API error, got %v", err)
+	}
+	if s := cb.State(); s != CircuitOpen {
+		t.Fatalf("expected open circuit, got %s", s)
+	}
+
+	// A succeeding probe closes it.
+	time.Sleep(60 * time.Millisecond)
+	atomic.StoreInt32(&failing, 0)
+	if _, _, err := env.Client.Zone.GetByID(ctx, "1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s := cb.State(); s != CircuitClosed {
+		t.Fatalf("expected closed circuit, got %s", s)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	expected := []string{
+		"closed->open",
+		"open->half-open", "half-open->open",
+		"open->half-open", "half-open->closed",
+	}
+	if !reflect.DeepEqual(transitions, expected) {
+		t.Errorf("expected transitions %v, got %v", expected, transitions)
+	}
+}
+
+func TestCircuitBreakerIgnoresUnsentRequests(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerOpts{Threshold: 1, Cooldown: time.Hour})
+	l := NewRateLimiter()
+	l.Update(Ratelimit{Limit: 1, Remaining: 0, Reset: time.Now().Add(time.Hour)})
+	env := newTestEnv(WithCircuitBreaker(cb), WithRateLimiter(l))
+	defer env.Teardown()
+
+	var calls int32
+	env.Mux.HandleFunc("/zones/1", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := env.Client.Zone.GetByID(ctx, "1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 0 {
+		t.Fatalf("expected no calls, got %d", n)
+	}
+	if s := cb.State(); s != CircuitClosed {
+		t.Errorf("expected closed circuit, got %s", s)
+	}
+}
@@ -4,13 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/alxrem/hdns-go/hdns/schema"
 	"io"
 	"io/ioutil"
+	"log/slog"
 	"math"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
 	"strconv"
 	"strings"
@@ -44,20 +45,112 @@ func ExponentialBackoff(b float64, d time.Duration) BackoffFunc {
 	}
 }
 
+// A RateLimitStrategy returns the duration to wait before retrying a request
+// which was rejected because the rate limit was exceeded. The resp argument
+// is the rejected response, retries and backoff are the number of retries
+// already performed and the BackoffFunc configured for the Client.
+type RateLimitStrategy func(resp *Response, retries int, backoff BackoffFunc) time.Duration
+
+// BackoffRateLimitStrategy is a RateLimitStrategy which always waits as long
+// as the BackoffFunc tells.
+func BackoffRateLimitStrategy(_ *Response, retries int, backoff BackoffFunc) time.Duration {
+	return backoff(retries)
+}
+
+// maxRateLimitWait is the longest duration HeaderRateLimitStrategy waits,
+// so a far-future RateLimit-Reset or Retry-After doesn't block a request
+// without deadline indefinitely.
+const maxRateLimitWait = time.Minute
+
+// HeaderRateLimitStrategy is a RateLimitStrategy which waits as long as
+// the Retry-After header tells or until the time given by the
+// RateLimit-Reset header, but at most one minute. Without these headers it
+// falls back to the BackoffFunc.
+func HeaderRateLimitStrategy(resp *Response, retries int, backoff BackoffFunc) time.Duration {
+	if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		return capRateLimitWait(d)
+	}
+	if reset := resp.Meta.Ratelimit.Reset; !reset.IsZero() {
+		if d := time.Until(reset); d > 0 {
+			return capRateLimitWait(d)
+		}
+	}
+	return backoff(retries)
+}
+
+func capRateLimitWait(d time.Duration) time.Duration {
+	if d > maxRateLimitWait {
+		return maxRateLimitWait
+	}
+	return d
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either
+// a number of seconds or an HTTP date.
+func parseRetryAfter(h string) (time.Duration, bool) {
+	if h == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(h); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(h); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// A Doer sends an HTTP request and returns an HTTP response. It is
+// implemented by *http.Client.
+type Doer interface {
+	Do(r *http.Request) (*http.Response, error)
+}
+
+// The DoerFunc type is an adapter to allow the use of ordinary functions
+// as Doer.
+type DoerFunc func(r *http.Request) (*http.Response, error)
+
+// Do calls f(r).
+func (f DoerFunc) Do(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// A Middleware wraps the Doer sending the requests of a Client, e.g. to
+// modify requests or inspect responses.
+type Middleware func(next Doer) Doer
+
 // Client is a client for the Hetzner Cloud API.
 type Client struct {
 	endpoint           string
 	token              string
 	pollInterval       time.Duration
 	backoffFunc        BackoffFunc
+	rateLimitStrategy  RateLimitStrategy
+	maxRetries         int
+	retryPolicy        RetryPolicy
+	rateLimiter        *RateLimiter
 	httpClient         *http.Client
+	middlewares        []Middleware
+	doer               Doer
 	applicationName    string
 	applicationVersion string
 	userAgent          string
 	debugWriter        io.Writer
-
-	Zone   ZoneClient
-	Record RecordClient
+	debugMode          DebugMode
+	logger             *slog.Logger
+	instrumenter       Instrumenter
+	tracer             Tracer
+	circuitBreaker     *CircuitBreaker
+
+	Zone          ZoneClient
+	Record        RecordClient
+	PrimaryServer PrimaryServerClient
 }
 
 // A ClientOption is used to configure a Client.
@@ -92,6 +185,58 @@ func WithBackoffFunc(f BackoffFunc) ClientOption {
 	}
 }
 
+// WithRateLimitStrategy configures a Client to use the specified strategy to
+// determine how long to wait when the rate limit is exceeded.
+func WithRateLimitStrategy(s RateLimitStrategy) ClientOption {
+	return func(client *Client) {
+		client.rateLimitStrategy = s
+	}
+}
+
+// WithMaxRetries configures a Client to retry a request at most maxRetries
+// times. A negative value means no limit.
+func WithMaxRetries(maxRetries int) ClientOption {
+	return func(client *Client) {
+		client.maxRetries = maxRetries
+	}
+}
+
+// WithRetryPolicy configures a Client to use the specified policy to decide
+// whether failed requests are retried. Requests exceeding the rate limit are
+// retried regardless of the policy. A nil policy disables these retries.
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(client *Client) {
+		client.retryPolicy = p
+	}
+}
+
+// WithRateLimiter configures a Client to throttle requests with the given
+// RateLimiter. The same RateLimiter may be passed to several Clients.
+func WithRateLimiter(l *RateLimiter) ClientOption {
+	return func(client *Client) {
+		client.rateLimiter = l
+	}
+}
+
+// WithHTTPClient configures a Client to perform HTTP requests with httpClient.
+// A nil httpClient is ignored.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(client *Client) {
+		if httpClient != nil {
+			client.httpClient = httpClient
+		}
+	}
+}
+
+// WithMiddleware configures a Client to send its requests through the given
+// Middleware. Middlewares are applied in the order they are configured, so
+// the first one sees the requests first.
+func WithMiddleware(m Middleware) ClientOption {
+	return func(client *Client) {
+		client.middlewares = append(client.middlewares, m)
+	}
+}
+
 // WithApplication configures a Client with the given application name and
 // application version. The version may be blank. Programs are encouraged
 // to at least set an application name.
@@ -104,19 +249,63 @@ func WithApplication(name, version string) ClientOption {
 
 // WithDebugWriter configures a Client to print debug information to the given
 // writer. To, for example, print debug information on stderr, set it to os.Stderr.
+// Credentials are redacted unless configured otherwise with WithDebugMode.
 func WithDebugWriter(debugWriter io.Writer) ClientOption {
 	return func(client *Client) {
 		client.debugWriter = debugWriter
 	}
 }
 
+// WithDebugMode configures a Client to print debug information in the
+// specified mode. It defaults to DebugModeDump.
+func WithDebugMode(mode DebugMode) ClientOption {
+	return func(client *Client) {
+		client.debugMode = mode
+	}
+}
+
+// WithLogger configures a Client to log every API call to the given logger.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(client *Client) {
+		client.logger = logger
+	}
+}
+
+// WithInstrumenter configures a Client to report measurements of its API
+// calls to the given Instrumenter.
+func WithInstrumenter(i Instrumenter) ClientOption {
+	return func(client *Client) {
+		client.instrumenter = i
+	}
+}
+
+// WithTracer configures a Client to trace its operations with the given
+// Tracer.
+func WithTracer(t Tracer) ClientOption {
+	return func(client *Client) {
+		client.tracer = t
+	}
+}
+
+// WithCircuitBreaker configures a Client to guard its requests with the
+// given CircuitBreaker. The same CircuitBreaker may be passed to several
+// Clients.
+func WithCircuitBreaker(cb *CircuitBreaker) ClientOption {
+	return func(client *Client) {
+		client.circuitBreaker = cb
+	}
+}
+
 // NewClient creates a new client.
 func NewClient(options ...ClientOption) *Client {
 	client := &Client{
-		endpoint:     Endpoint,
-		httpClient:   &http.Client{},
-		backoffFunc:  ExponentialBackoff(2, 500*time.Millisecond),
-		pollInterval: 500 * time.Millisecond,
+		endpoint:          Endpoint,
+		httpClient:        &http.Client{},
+		backoffFunc:       ExponentialBackoff(2, 500*time.Millisecond),
+		rateLimitStrategy: HeaderRateLimitStrategy,
+		maxRetries:        5,
+		retryPolicy:       DefaultRetryPolicy,
+		pollInterval:      500 * time.Millisecond,
 	}
 
 	for _, option := range options {
@@ -124,9 +313,11 @@ func NewClient(options ...ClientOption) *Client {
 	}
 
 	client.buildUserAgent()
+	client.buildDoer()
 
 	client.Zone = ZoneClient{client: client}
 	client.Record = RecordClient{client: client}
+	client.PrimaryServer = PrimaryServerClient{client: client}
 
 	return client
 }
@@ -134,6 +325,12 @@ func NewClient(options ...ClientOption) *Client {
 // NewRequest creates an HTTP request against the API. The returned request
 // is assigned with ctx and has all necessary headers set (auth, user agent, etc.).
 func (c *Client) NewRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	return c.NewRequestWithContentType(ctx, method, path, "application/json", body)
+}
+
+// NewRequestWithContentType creates an HTTP request like NewRequest does,
+// but sends the body with the given content type.
+func (c *Client) NewRequestWithContentType(ctx context.Context, method, path, contentType string, body io.Reader) (*http.Request, error) {
 	apiUrl := c.endpoint + path
 	req, err := http.NewRequest(method, apiUrl, body)
 	if err != nil {
@@ -142,61 +339,129 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, body io.Re
 	req.Header.Set("User-Agent", c.userAgent)
 	req.Header.Set("Auth-API-Token", c.token)
 	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Type", contentType)
 	}
 	req = req.WithContext(ctx)
 	return req, nil
 }
 
-// Do performs an HTTP request against the API.
+// Do performs an HTTP request against the API. If the rate limit is
+// exceeded or the RetryPolicy of the Client allows to retry a failed
+// request, the request is retried after backing off, at most as often as
+// configured with WithMaxRetries.
 func (c *Client) Do(r *http.Request, v interface{}) (*Response, error) {
+	start := time.Now()
+	resp, retries, err := c.do(r, v)
+	c.logRequest(r, resp, retries, time.Since(start), err)
+	observeCall(r.Context(), resp, retries, err)
+	return resp, err
+}
+
+// do performs an HTTP request like Do does and additionally returns the
+// number of retries performed.
+func (c *Client) do(r *http.Request, v interface{}) (*Response, int, error) {
 	var retries int
+	if err := bufferRequestBody(r); err != nil {
+		return nil, retries, err
+	}
+	endpoint := normalizeEndpoint(c.apiPath(r.URL))
+
 	for {
-		if c.debugWriter != nil {
-			dumpReq, err := httputil.DumpRequest(r, true)
+		if r.GetBody != nil {
+			reqBody, err := r.GetBody()
 			if err != nil {
-				return nil, err
+				return nil, retries, err
 			}
-			fmt.Fprintf(c.debugWriter, "--- Request:\n%s\n\n", dumpReq)
+			r.Body = reqBody
 		}
 
-		resp, err := c.httpClient.Do(r)
+		if c.rateLimiter != nil {
+			if err := c.rateLimiter.Wait(r.Context()); err != nil {
+				return nil, retries, err
+			}
+		}
+
+		if err := c.debugRequest(r); err != nil {
+			return nil, retries, err
+		}
+
+		start := time.Now()
+		resp, err := c.send(r)
+		if errors.Is(err, ErrCircuitOpen) {
+			return nil, retries, err
+		}
 		if err != nil {
-			return nil, err
+			c.observeRequest(r.Method, endpoint, 0, time.Since(start))
+			if err := c.debugResponse(r, nil, nil, time.Since(start), err); err != nil {
+				return nil, retries, err
+			}
+			if c.retry(r, 0, err, retries) {
+				c.observeRetry(r.Method, endpoint, false)
+				if err := c.backoff(r.Context(), c.backoffFunc(retries)); err != nil {
+					return nil, retries, err
+				}
+				retries++
+				continue
+			}
+			return nil, retries, err
 		}
 		response := &Response{Response: resp}
 		body, err := ioutil.ReadAll(resp.Body)
 		if err != nil {
 			resp.Body.Close()
-			return response, err
+			c.observeRequest(r.Method, endpoint, resp.StatusCode, time.Since(start))
+			if err := c.debugResponse(r, nil, nil, time.Since(start), err); err != nil {
+				return response, retries, err
+			}
+			if c.retry(r, 0, err, retries) {
+				c.observeRetry(r.Method, endpoint, false)
+				if err := c.backoff(r.Context(), c.backoffFunc(retries)); err != nil {
+					return response, retries, err
+				}
+				retries++
+				continue
+			}
+			return response, retries, err
 		}
 		resp.Body.Close()
 		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		c.observeRequest(r.Method, endpoint, resp.StatusCode, time.Since(start))
 
-		if c.debugWriter != nil {
-			dumpResp, err := httputil.DumpResponse(resp, true)
-			if err != nil {
-				return nil, err
-			}
-			fmt.Fprintf(c.debugWriter, "--- Response:\n%s\n\n", dumpResp)
+		if err := c.debugResponse(r, resp, body, time.Since(start), nil); err != nil {
+			return nil, retries, err
 		}
 
-		if err = response.readMeta(body); err != nil {
-			return response, fmt.Errorf("hdns: error reading response meta data: %s", err)
+		// Failed responses are handled by their status code, gateways often
+		// send them with an empty or non-JSON body despite the content type.
+		if err = response.readMeta(body); err != nil && resp.StatusCode < 400 {
+			return response, retries, fmt.Errorf("hdns: error reading response meta data: %s", err)
+		}
+		if c.rateLimiter != nil && resp.Header.Get("RateLimit-Remaining") != "" {
+			c.rateLimiter.Update(response.Meta.Ratelimit)
 		}
+		c.observeRateLimit(response)
 
 		if resp.StatusCode >= 400 && resp.StatusCode <= 599 {
-			err = errorFromResponse(resp, body)
-			if err == nil {
-				err = fmt.Errorf("hdns: server responded with status code %d", resp.StatusCode)
-			} else {
-				if err, ok := err.(Error); ok && err.Code == ErrorCodeRateLimitExceeded {
-					c.backoff(retries)
+			err = c.errorFromResponse(r, resp, body)
+			if errors.Is(err, ErrRateLimited) {
+				if c.canRetry(retries) {
+					c.observeRetry(r.Method, endpoint, true)
+					wait := c.rateLimitStrategy(response, retries, c.backoffFunc)
+					if err := c.backoff(r.Context(), wait); err != nil {
+						return response, retries, err
+					}
 					retries++
 					continue
 				}
+			} else if c.retry(r, resp.StatusCode, err, retries) {
+				c.observeRetry(r.Method, endpoint, false)
+				if err := c.backoff(r.Context(), c.backoffFunc(retries)); err != nil {
+					return response, retries, err
+				}
+				retries++
+				continue
 			}
-			return response, err
+			return response, retries, err
 		}
 		if v != nil {
 			if w, ok := v.(io.Writer); ok {
@@ -206,12 +471,53 @@ func (c *Client) Do(r *http.Request, v interface{}) (*Response, error) {
 			}
 		}
 
-		return response, err
+		return response, retries, err
+	}
+}
+
+// canRetry returns whether another retry is allowed after retries retries.
+func (c *Client) canRetry(retries int) bool {
+	return c.maxRetries < 0 || retries < c.maxRetries
+}
+
+// retry returns whether the failed request r is retried according to the
+// RetryPolicy of the Client.
+func (c *Client) retry(r *http.Request, statusCode int, err error, retries int) bool {
+	if c.retryPolicy == nil || !c.canRetry(retries) || r.Context().Err() != nil {
+		return false
 	}
+	return c.retryPolicy.Retry(r.Method, statusCode, err, retries)
 }
 
-func (c *Client) backoff(retries int) {
-	time.Sleep(c.backoffFunc(retries))
+// backoff waits for d before the next retry. It returns early with the error
+// of ctx if ctx is done before.
+func (c *Client) backoff(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// bufferRequestBody reads the body of r into memory unless it can already be
+// obtained again via r.GetBody, so that the request can be retried.
+func bufferRequestBody(r *http.Request) error {
+	if r.Body == nil || r.Body == http.NoBody || r.GetBody != nil {
+		return nil
+	}
+	data, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return err
+	}
+	r.ContentLength = int64(len(data))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	}
+	return nil
 }
 
 func (c *Client) all(f func(int) (*Response, error)) (*Response, error) {
@@ -223,10 +529,25 @@ func (c *Client) all(f func(int) (*Response, error)) (*Response, error) {
 		if err != nil {
 			return nil, err
 		}
-		if resp.Meta.Pagination == nil || resp.Meta.Pagination.NextPage == 0 {
+		p := resp.Meta.Pagination
+		switch {
+		case p == nil:
+			return resp, nil
+		case p.NextPage > page:
+			page = p.NextPage
+		case p.Page >= page && p.Page < p.LastPage:
+			// The API may omit next_page, so fall back to last_page.
+			page = p.Page + 1
+		default:
 			return resp, nil
 		}
-		page = resp.Meta.Pagination.NextPage
+	}
+}
+
+func (c *Client) buildDoer() {
+	c.doer = c.httpClient
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		c.doer = c.middlewares[i](c.doer)
 	}
 }
 
@@ -241,19 +562,42 @@ func (c *Client) buildUserAgent() {
 	}
 }
 
-func errorFromResponse(resp *http.Response, body []byte) error {
-	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
-		return nil
+// errorFromResponse returns the Error for the failed request r. If the body
+// of the response isn't a JSON error, the status code is used as error code.
+// Errors caused by invalid input are returned as *ValidationError.
+func (c *Client) errorFromResponse(r *http.Request, resp *http.Response, body []byte) error {
+	apiErr := Error{
+		Code:       ErrorCode(resp.StatusCode),
+		Message:    http.StatusText(resp.StatusCode),
+		StatusCode: resp.StatusCode,
+		Method:     r.Method,
+		Path:       c.apiPath(r.URL),
+		Body:       body,
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = fmt.Sprintf("server responded with status code %d", resp.StatusCode)
 	}
 
 	var respBody schema.ErrorResponse
-	if err := json.Unmarshal(body, &respBody); err != nil {
-		return nil
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		if err := json.Unmarshal(body, &respBody); err != nil {
+			respBody = schema.ErrorResponse{}
+		}
 	}
-	if respBody.Error.Code == 0 && respBody.Error.Message == "" {
-		return nil
+	if respBody.Error.Code != 0 {
+		apiErr.Code = ErrorCode(respBody.Error.Code)
+	}
+	if respBody.Error.Message != "" {
+		apiErr.Message = respBody.Error.Message
+	}
+
+	if details, ok := respBody.Error.Details.(schema.ErrorDetailsInvalidInput); ok {
+		return ValidationErrorFromSchema(details, apiErr)
+	}
+	if apiErr.Code == ErrorCodeInvalidInput {
+		return &ValidationError{Err: apiErr}
 	}
-	return ErrorFromSchema(respBody.Error)
+	return apiErr
 }
 
 // Response represents a response from the API. It embeds http.Response.
@@ -0,0 +1,332 @@
+package hdns
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testEnv struct {
+	Server *httptest.Server
+	Mux    *http.ServeMux
+	Client *Client
+}
+
+func newTestEnv(options ...ClientOption) testEnv {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	options = append([]ClientOption{
+		WithEndpoint(server.URL),
+		WithToken("token"),
+		WithBackoffFunc(ConstantBackoff(time.Millisecond)),
+	}, options...)
+	return testEnv{
+		Server: server,
+		Mux:    mux,
+		Client: NewClient(options...),
+	}
+}
+
+func (env *testEnv) Teardown() {
+	env.Server.Close()
+}
+
+// readerOnly hides all methods but Read, so http.NewRequest can't set up
+// GetBody for it.
+type readerOnly struct {
+	r *strings.Reader
+}
+
+func (r readerOnly) Read(p []byte) (int, error) {
+	return r.r.Read(p)
+}
+
+func TestClientDoRetryResendsBody(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	var calls int32
+	env.Mux.HandleFunc("/zones/1/import", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != "zone file" {
+			t.Errorf("unexpected body: %q", body)
+		}
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"zone":{"id":"1"}}`))
+	})
+
+	for _, body := range []struct {
+		name string
+		r    func() io.Reader
+	}{
+		{"rewindable", func() io.Reader { return strings.NewReader("zone file") }},
+		{"plain reader", func() io.Reader { return readerOnly{strings.NewReader("zone file")} }},
+	} {
+		atomic.StoreInt32(&calls, 0)
+		zone, _, err := env.Client.Zone.ImportZoneFile(context.Background(), "1", body.r())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", body.name, err)
+		}
+		if zone.ID != "1" {
+			t.Errorf("%s: unexpected zone ID: %s", body.name, zone.ID)
+		}
+		if n := atomic.LoadInt32(&calls); n != 3 {
+			t.Errorf("%s: expected 3 calls, got %d", body.name, n)
+		}
+	}
+}
+
+func TestClientDoBackoffCanceled(t *testing.T) {
+	env := newTestEnv(WithBackoffFunc(ConstantBackoff(time.Hour)), WithRateLimitStrategy(BackoffRateLimitStrategy))
+	defer env.Teardown()
+
+	env.Mux.HandleFunc("/zones/1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, _, err := env.Client.Zone.GetByID(ctx, "1")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("backoff was not interrupted, took %s", d)
+	}
+}
+
+func TestClientDoMaxRetries(t *testing.T) {
+	env := newTestEnv(WithMaxRetries(2))
+	defer env.Teardown()
+
+	var calls int32
+	env.Mux.HandleFunc("/zones/1", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	_, _, err := env.Client.Zone.GetByID(context.Background(), "1")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Errorf("expected 3 calls, got %d", n)
+	}
+}
+
+func TestClientDoRetryPolicy(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	var calls int32
+	env.Mux.HandleFunc("/zones", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	env.Mux.HandleFunc("/zones/1", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"zone":{"id":"1"}}`))
+	})
+
+	zone, _, err := env.Client.Zone.GetByID(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if zone == nil || zone.ID != "1" {
+		t.Errorf("unexpected zone: %v", zone)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	if _, _, err := env.Client.Zone.Create(context.Background(), ZoneCreateOpts{Name: "example.com"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("expected POST not to be retried, got %d calls", n)
+	}
+}
+
+func TestClientWithHTTPClientNil(t *testing.T) {
+	env := newTestEnv(WithHTTPClient(nil))
+	defer env.Teardown()
+
+	env.Mux.HandleFunc("/zones/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"zone":{"id":"1"}}`))
+	})
+
+	if _, _, err := env.Client.Zone.GetByID(context.Background(), "1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestClientWithMiddleware(t *testing.T) {
+	var order []string
+	middleware := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(r *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				r.Header.Set("X-"+name, "1")
+				return next.Do(r)
+			})
+		}
+	}
+	env := newTestEnv(WithMiddleware(middleware("First")), WithMiddleware(middleware("Second")))
+	defer env.Teardown()
+
+	env.Mux.HandleFunc("/zones/1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-First") == "" || r.Header.Get("X-Second") == "" {
+			t.Error("middleware headers missing")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"zone":{"id":"1"}}`))
+	})
+
+	if _, _, err := env.Client.Zone.GetByID(context.Background(), "1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if strings.Join(order, ",") != "First,Second" {
+		t.Errorf("unexpected middleware order: %v", order)
+	}
+}
+
+func TestClientErrorUnexpectedDetails(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	env.Mux.HandleFunc("/records", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"error":{"code":422,"message":"ttl out of range","details":"oops"}}`))
+	})
+
+	_, _, err := env.Client.Record.Create(context.Background(), RecordCreateOpts{
+		Name:   "www",
+		Type:   RecordTypeA,
+		Value:  "127.0.0.1",
+		ZoneID: "1",
+	})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if validationErr.Err.Code != ErrorCodeInvalidInput {
+		t.Errorf("unexpected code: %d", validationErr.Err.Code)
+	}
+	if validationErr.Err.Message != "ttl out of range" {
+		t.Errorf("unexpected message: %q", validationErr.Err.Message)
+	}
+}
+
+func TestClientDoRetriesEmptyJSONBody(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	var calls int32
+	env.Mux.HandleFunc("/zones/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"zone":{"id":"1"}}`))
+	})
+
+	zone, _, err := env.Client.Zone.GetByID(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if zone == nil || zone.ID != "1" {
+		t.Errorf("unexpected zone: %v", zone)
+	}
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Errorf("expected 3 calls, got %d", n)
+	}
+}
+
+func TestClientDoErrorFromNonJSONBody(t *testing.T) {
+	env := newTestEnv(WithMaxRetries(0))
+	defer env.Teardown()
+
+	env.Mux.HandleFunc("/zones/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("<html>Too Many Requests</html>"))
+	})
+
+	_, _, err := env.Client.Zone.GetByID(context.Background(), "1")
+	var apiErr Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected Error, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("unexpected status code: %d", apiErr.StatusCode)
+	}
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected rate limit error, got %v", err)
+	}
+}
+
+func TestClientNewRequestWithContentType(t *testing.T) {
+	client := NewClient(WithToken("token"))
+
+	req, err := client.NewRequestWithContentType(context.Background(), "POST", "/zones/1/import", "text/plain", strings.NewReader("zone file"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := req.Header.Get("Content-Type"); h != "text/plain" {
+		t.Errorf("unexpected content type: %q", h)
+	}
+	if h := req.Header.Get("Auth-API-Token"); h != "token" {
+		t.Errorf("unexpected token: %q", h)
+	}
+}
+
+func TestHeaderRateLimitStrategy(t *testing.T) {
+	backoff := ConstantBackoff(3 * time.Second)
+	response := func(retryAfter string, reset time.Time) *Response {
+		resp := &Response{Response: &http.Response{Header: http.Header{}}}
+		if retryAfter != "" {
+			resp.Header.Set("Retry-After", retryAfter)
+		}
+		resp.Meta.Ratelimit.Reset = reset
+		return resp
+	}
+	now := time.Now()
+
+	for _, tt := range []struct {
+		name     string
+		resp     *Response
+		min, max time.Duration
+	}{
+		{"seconds", response("5", time.Time{}), 5 * time.Second, 5 * time.Second},
+		{"seconds before reset", response("5", now.Add(time.Hour)), 5 * time.Second, 5 * time.Second},
+		{"HTTP date", response(now.Add(10*time.Second).UTC().Format(http.TimeFormat), time.Time{}), 8 * time.Second, 10 * time.Second},
+		{"past HTTP date", response(now.Add(-time.Hour).UTC().Format(http.TimeFormat), time.Time{}), 0, 0},
+		{"reset", response("", now.Add(20*time.Second)), 19 * time.Second, 20 * time.Second},
+		{"invalid header falls back to reset", response("soon", now.Add(20*time.Second)), 19 * time.Second, 20 * time.Second},
+		{"past reset falls back to backoff", response("", now.Add(-time.Second)), 3 * time.Second, 3 * time.Second},
+		{"backoff", response("", time.Time{}), 3 * time.Second, 3 * time.Second},
+		{"seconds capped", response("86400", time.Time{}), maxRateLimitWait, maxRateLimitWait},
+		{"reset capped", response("", now.Add(24*time.Hour)), maxRateLimitWait, maxRateLimitWait},
+	} {
+		if d := HeaderRateLimitStrategy(tt.resp, 0, backoff); d < tt.min || d > tt.max {
+			t.Errorf("%s: expected to wait between %s and %s, got %s", tt.name, tt.min, tt.max, d)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package hdns
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"time"
+)
+
+// DebugMode specifies how a Client prints debug information.
+type DebugMode int
+
+const (
+	// DebugModeDump prints dumps of requests and responses with
+	// credentials redacted.
+	DebugModeDump DebugMode = iota
+	// DebugModeRawDump prints dumps of requests and responses as they
+	// are, including the API token.
+	DebugModeRawDump
+	// DebugModeStructured prints one line per request with the method,
+	// URL, status, duration and truncated bodies.
+	DebugModeStructured
+)
+
+// debugBodyLimit is the maximum number of bytes of a body printed in
+// DebugModeStructured.
+const debugBodyLimit = 1024
+
+// redactedHeaders are the headers whose values are redacted in debug output.
+var redactedHeaders = []string{"Auth-API-Token", "Authorization", "Cookie", "Set-Cookie"}
+
+func redactHeader(h http.Header) http.Header {
+	redacted := make(http.Header, len(h))
+	for k, v := range h {
+		redacted[k] = v
+	}
+	for _, k := range redactedHeaders {
+		if redacted.Get(k) != "" {
+			redacted.Set(k, "REDACTED")
+		}
+	}
+	return redacted
+}
+
+// debugRequest prints debug information about r before it is sent.
+func (c *Client) debugRequest(r *http.Request) error {
+	if c.debugWriter == nil || c.debugMode == DebugModeStructured {
+		return nil
+	}
+
+	dumped := *r
+	if c.debugMode != DebugModeRawDump {
+		dumped.Header = redactHeader(r.Header)
+	}
+	dumpReq, err := httputil.DumpRequest(&dumped, true)
+	if err != nil {
+		return err
+	}
+	// DumpRequest consumed the body of r and replaced it on the copy.
+	r.Body = dumped.Body
+	fmt.Fprintf(c.debugWriter, "--- Request:\n%s\n\n", dumpReq)
+	return nil
+}
+
+// debugResponse prints debug information about the response resp with the
+// given body, which was received for r after duration d. If the request
+// failed, resp is nil and err is the error.
+func (c *Client) debugResponse(r *http.Request, resp *http.Response, body []byte, d time.Duration, err error) error {
+	if c.debugWriter == nil {
+		return nil
+	}
+
+	if c.debugMode != DebugModeStructured {
+		if resp == nil {
+			return nil
+		}
+		dumped := *resp
+		if c.debugMode != DebugModeRawDump {
+			dumped.Header = redactHeader(resp.Header)
+		}
+		dumpResp, err := httputil.DumpResponse(&dumped, true)
+		if err != nil {
+			return err
+		}
+		resp.Body = dumped.Body
+		fmt.Fprintf(c.debugWriter, "--- Response:\n%s\n\n", dumpResp)
+		return nil
+	}
+
+	var reqBody []byte
+	if r.GetBody != nil {
+		if rc, err := r.GetBody(); err == nil {
+			reqBody, _ = ioutil.ReadAll(rc)
+			rc.Close()
+		}
+	}
+	line := fmt.Sprintf("method=%s url=%q", r.Method, r.URL.String())
+	if resp != nil {
+		line += fmt.Sprintf(" status=%d", resp.StatusCode)
+	}
+	line += fmt.Sprintf(" duration=%s", d)
+	if err != nil {
+		line += fmt.Sprintf(" error=%q", err.Error())
+	}
+	if len(reqBody) > 0 {
+		line += fmt.Sprintf(" request_body=%q", truncateDebugBody(reqBody))
+	}
+	if len(body) > 0 {
+		line += fmt.Sprintf(" response_body=%q", truncateDebugBody(body))
+	}
+	fmt.Fprintln(c.debugWriter, line)
+	return nil
+}
+
+func truncateDebugBody(body []byte) string {
+	if len(body) <= debugBodyLimit {
+		return string(body)
+	}
+	return string(body[:debugBodyLimit]) + "..."
+}
@@ -0,0 +1,43 @@
+package hdns
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClientDebugRedactsToken(t *testing.T) {
+	const token = "secret-token"
+
+	for _, tt := range []struct {
+		mode     DebugMode
+		redacted bool
+	}{
+		{DebugModeDump, true},
+		{DebugModeStructured, true},
+		{DebugModeRawDump, false},
+	} {
+		var buf bytes.Buffer
+		env := newTestEnv(WithToken(token), WithDebugWriter(&buf), WithDebugMode(tt.mode))
+		env.Mux.HandleFunc("/zones/1", func(w http.ResponseWriter, r *http.Request) {
+			// Echo the token to make sure it's redacted in responses too.
+			w.Header().Set("Auth-API-Token", r.Header.Get("Auth-API-Token"))
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"zone":{"id":"1"}}`))
+		})
+
+		_, _, err := env.Client.Zone.GetByID(context.Background(), "1")
+		env.Teardown()
+		if err != nil {
+			t.Fatalf("mode %d: unexpected error: %s", tt.mode, err)
+		}
+		if buf.Len() == 0 {
+			t.Fatalf("mode %d: no debug output", tt.mode)
+		}
+		if contains := strings.Contains(buf.String(), token); contains == tt.redacted {
+			t.Errorf("mode %d: expected token in output to be %v, got output:\n%s", tt.mode, !tt.redacted, buf.String())
+		}
+	}
+}
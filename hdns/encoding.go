@@ -0,0 +1,139 @@
+package hdns
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/alxrem/hdns-go/hdns/schema"
+)
+
+// This file provides stable JSON and YAML encodings of Zone and Record,
+// which need to merge the fields in Extra. They are encoded like the API
+// encodes the corresponding models in the schema package, so they can be
+// saved and loaded again without losing data. The other models are encoded
+// by their struct tags. The YAML methods follow the interfaces of the common
+// YAML packages, e.g. gopkg.in/yaml.v3, without depending on any of them.
+//
+// The methods are promoted to structs embedding a Zone or Record, which are
+// then encoded as the Zone or Record alone, without their own fields.
+
+// MarshalJSON encodes the Zone like the API does.
+func (z Zone) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ZoneToSchema(z))
+}
+
+// UnmarshalJSON decodes a Zone encoded like the API does.
+func (z *Zone) UnmarshalJSON(data []byte) error {
+	var s schema.Zone
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*z = *ZoneFromSchema(s)
+	return nil
+}
+
+// MarshalYAML encodes the Zone like MarshalJSON does.
+func (z Zone) MarshalYAML() (interface{}, error) {
+	return marshalYAML(z)
+}
+
+// UnmarshalYAML decodes a Zone encoded by MarshalYAML.
+func (z *Zone) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(unmarshal, z)
+}
+
+// MarshalJSON encodes the Record like the API does.
+func (r Record) MarshalJSON() ([]byte, error) {
+	return json.Marshal(RecordToSchema(r))
+}
+
+// UnmarshalJSON decodes a Record encoded like the API does.
+func (r *Record) UnmarshalJSON(data []byte) error {
+	var s schema.Record
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*r = *RecordFromSchema(s)
+	return nil
+}
+
+// MarshalYAML encodes the Record like MarshalJSON does.
+func (r Record) MarshalYAML() (interface{}, error) {
+	return marshalYAML(r)
+}
+
+// UnmarshalYAML decodes a Record encoded by MarshalYAML.
+func (r *Record) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return unmarshalYAML(unmarshal, r)
+}
+
+// marshalYAML returns the JSON encoding of v as generic value, which YAML
+// packages encode with the same structure and sorted keys.
+func marshalYAML(v json.Marshaler) (interface{}, error) {
+	data, err := v.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// unmarshalYAML decodes a YAML value into v by the way of its JSON encoding.
+func unmarshalYAML(unmarshal func(interface{}) error, v json.Unmarshaler) error {
+	var value interface{}
+	if err := unmarshal(&value); err != nil {
+		return err
+	}
+	value, err := jsonValue(value)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return v.UnmarshalJSON(data)
+}
+
+// jsonValue converts the maps with arbitrary keys some YAML packages decode
+// to into maps encodable as JSON.
+func jsonValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			k, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("hdns: unsupported YAML key %v", key)
+			}
+			converted, err := jsonValue(value)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = converted
+		}
+		return m, nil
+	case map[string]interface{}:
+		for key, value := range v {
+			converted, err := jsonValue(value)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = converted
+		}
+		return v, nil
+	case []interface{}:
+		for i, value := range v {
+			converted, err := jsonValue(value)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = converted
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,159 @@
+package hdns
+
+import (
+	"encoding/json"
+	"github.com/alxrem/hdns-go/hdns/schema"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPrimaryServerJSON(t *testing.T) {
+	created := schema.Time{Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	primaryServer := PrimaryServer{
+		ID:      "1",
+		Address: "192.0.2.1",
+		Port:    53,
+		ZoneID:  "2",
+		Created: created,
+	}
+
+	data, err := json.Marshal(primaryServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"id":"1","address":"192.0.2.1","port":53,"zone_id":"2","created":"2024-01-02T03:04:05Z","modified":""}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	var decoded PrimaryServer
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, primaryServer) {
+		t.Errorf("expected %+v, got %+v", primaryServer, decoded)
+	}
+}
+
+func TestBaseRecordEmbeddedJSON(t *testing.T) {
+	v := struct {
+		BaseRecord
+		Comment string `json:"comment"`
+	}{
+		BaseRecord: BaseRecord{Name: "www", TTL: 60, Type: RecordTypeA, Value: "192.0.2.1", ZoneID: "1"},
+		Comment:    "web server",
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"name":"www","ttl":60,"type":"A","value":"192.0.2.1","zone_id":"1","comment":"web server"}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+}
+
+func TestZoneRoundTrip(t *testing.T) {
+	var s schema.Zone
+	if err := json.Unmarshal([]byte(`{"id":"1","name":"example.com","created":"2024-01-02 03:04:05.123 +0000 UTC","dnssec":{"enabled":true}}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	zone := *ZoneFromSchema(s)
+
+	data, err := json.Marshal(zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Zone
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	assertZoneRoundTrip(t, "JSON", zone, decoded)
+
+	value, err := zone.MarshalYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded = Zone{}
+	if err := decoded.UnmarshalYAML(yamlUnmarshaler(value)); err != nil {
+		t.Fatal(err)
+	}
+	assertZoneRoundTrip(t, "YAML", zone, decoded)
+}
+
+func assertZoneRoundTrip(t *testing.T, encoding string, zone, decoded Zone) {
+	t.Helper()
+	if decoded.ID != zone.ID || decoded.Name != zone.Name {
+		t.Errorf("%s: expected %+v, got %+v", encoding, zone, decoded)
+	}
+	if !decoded.Created.Equal(zone.Created.Time) || !decoded.Modified.IsZero() {
+		t.Errorf("%s: expected created %s, got %s", encoding, zone.Created, decoded.Created)
+	}
+	if extra := string(decoded.Extra["dnssec"]); extra != `{"enabled":true}` {
+		t.Errorf("%s: unexpected extra dnssec field: %s", encoding, extra)
+	}
+}
+
+func TestRecordRoundTrip(t *testing.T) {
+	var s schema.Record
+	if err := json.Unmarshal([]byte(`{"id":"2","name":"www","type":"A","value":"192.0.2.1","zone_id":"1","modified":"2024-01-02T03:04:05Z","comment":"web server"}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	record := *RecordFromSchema(s)
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Record
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	assertRecordRoundTrip(t, "JSON", record, decoded)
+
+	value, err := record.MarshalYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded = Record{}
+	if err := decoded.UnmarshalYAML(yamlUnmarshaler(value)); err != nil {
+		t.Fatal(err)
+	}
+	assertRecordRoundTrip(t, "YAML", record, decoded)
+}
+
+func assertRecordRoundTrip(t *testing.T, encoding string, record, decoded Record) {
+	t.Helper()
+	if decoded.ID != record.ID || decoded.BaseRecord != record.BaseRecord {
+		t.Errorf("%s: expected %+v, got %+v", encoding, record, decoded)
+	}
+	if !decoded.Modified.Equal(record.Modified.Time) || !decoded.Created.IsZero() {
+		t.Errorf("%s: expected modified %s, got %s", encoding, record.Modified, decoded.Modified)
+	}
+	if extra := string(decoded.Extra["comment"]); extra != `"web server"` {
+		t.Errorf("%s: unexpected extra comment field: %s", encoding, extra)
+	}
+}
+
+// yamlUnmarshaler returns an unmarshal function decoding value the way YAML
+// packages do, with interface{} map keys.
+func yamlUnmarshaler(value interface{}) func(interface{}) error {
+	var convert func(interface{}) interface{}
+	convert = func(v interface{}) interface{} {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		converted := make(map[interface{}]interface{}, len(m))
+		for key, value := range m {
+			converted[key] = convert(value)
+		}
+		return converted
+	}
+	return func(v interface{}) error {
+		*v.(*interface{}) = convert(value)
+		return nil
+	}
+}
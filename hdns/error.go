@@ -1,28 +1,99 @@
 package hdns
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // ErrorCode represents an error code returned from the API.
 type ErrorCode int
 
 // Error codes returned from the API.
 const (
+	ErrorCodeUnauthorized      ErrorCode = 401
+	ErrorCodeForbidden         ErrorCode = 403
 	ErrorCodeNotFound          ErrorCode = 404
+	ErrorCodeConflict          ErrorCode = 409
+	ErrorCodeInvalidInput      ErrorCode = 422
 	ErrorCodeRateLimitExceeded ErrorCode = 429
 )
 
+// Sentinel errors matching an Error with the corresponding error code
+// when used with errors.Is.
+var (
+	ErrUnauthorized = errors.New("hdns: unauthorized")
+	ErrForbidden    = errors.New("hdns: forbidden")
+	ErrNotFound     = errors.New("hdns: not found")
+	ErrConflict     = errors.New("hdns: conflict")
+	ErrInvalidInput = errors.New("hdns: invalid input")
+	ErrRateLimited  = errors.New("hdns: rate limit exceeded")
+)
+
+var sentinelErrorCodes = map[error]ErrorCode{
+	ErrUnauthorized: ErrorCodeUnauthorized,
+	ErrForbidden:    ErrorCodeForbidden,
+	ErrNotFound:     ErrorCodeNotFound,
+	ErrConflict:     ErrorCodeConflict,
+	ErrInvalidInput: ErrorCodeInvalidInput,
+	ErrRateLimited:  ErrorCodeRateLimitExceeded,
+}
+
 // Error is an error returned from the API.
 type Error struct {
 	Code    ErrorCode
 	Message string
+
+	StatusCode int    // HTTP status code of the response
+	Method     string // HTTP method of the request
+	Path       string // API path of the request
+	Body       []byte // raw body of the response
 }
 
 func (e Error) Error() string {
 	return fmt.Sprintf("%s (%d)", e.Message, e.Code)
 }
 
-// IsError returns whether err is an API error with the given error code.
+// Is reports whether e has the error code of the sentinel error target, e.g.
+// ErrNotFound.
+func (e Error) Is(target error) bool {
+	code, ok := sentinelErrorCodes[target]
+	return ok && (e.Code == code || ErrorCode(e.StatusCode) == code)
+}
+
+// IsError returns whether err is or wraps an API error with the given error
+// code.
 func IsError(err error, code ErrorCode) bool {
-	apiErr, ok := err.(Error)
-	return ok && apiErr.Code == code
+	var apiErr Error
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+// ValidationError is returned when the API rejects a request because of
+// invalid input. It wraps the Error returned from the API, so it matches
+// ErrInvalidInput when used with errors.Is.
+type ValidationError struct {
+	Err    Error
+	Fields []FieldError
+}
+
+// FieldError describes why the value of a single field is invalid.
+type FieldError struct {
+	Field    string
+	Messages []string
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Fields) == 0 {
+		return e.Err.Error()
+	}
+	fields := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		fields = append(fields, fmt.Sprintf("%s: %s", f.Field, strings.Join(f.Messages, ", ")))
+	}
+	return fmt.Sprintf("%s (%s)", e.Err.Error(), strings.Join(fields, "; "))
+}
+
+// Unwrap returns the Error returned from the API.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
 }
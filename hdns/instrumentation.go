@@ -0,0 +1,38 @@
+package hdns
+
+import "time"
+
+// An Instrumenter receives measurements of the API calls made by a Client.
+// The endpoint arguments are API paths with IDs replaced by a placeholder,
+// e.g. /records/{id}. Implementations must be safe for concurrent use.
+type Instrumenter interface {
+	// ObserveRequest is called after every HTTP request sent to the API,
+	// including retried ones. The statusCode is 0 if no response was
+	// received.
+	ObserveRequest(method, endpoint string, statusCode int, d time.Duration)
+	// ObserveRetry is called before a request is retried. The rateLimited
+	// argument reports whether the request is retried because the rate
+	// limit was exceeded.
+	ObserveRetry(method, endpoint string, rateLimited bool)
+	// ObserveRateLimit is called with the rate limit information of every
+	// response containing it.
+	ObserveRateLimit(r Ratelimit)
+}
+
+func (c *Client) observeRequest(method, endpoint string, statusCode int, d time.Duration) {
+	if c.instrumenter != nil {
+		c.instrumenter.ObserveRequest(method, endpoint, statusCode, d)
+	}
+}
+
+func (c *Client) observeRateLimit(r *Response) {
+	if c.instrumenter != nil && r.Header.Get("RateLimit-Remaining") != "" {
+		c.instrumenter.ObserveRateLimit(r.Meta.Ratelimit)
+	}
+}
+
+func (c *Client) observeRetry(method, endpoint string, rateLimited bool) {
+	if c.instrumenter != nil {
+		c.instrumenter.ObserveRetry(method, endpoint, rateLimited)
+	}
+}
@@ -0,0 +1,44 @@
+package hdns
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// logRequest logs an API call made by Do, if a logger is configured.
+func (c *Client) logRequest(r *http.Request, resp *Response, retries int, d time.Duration, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	path := c.apiPath(r.URL)
+	attrs := []slog.Attr{
+		slog.String("method", r.Method),
+		slog.String("path", path),
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	attrs = append(attrs,
+		slog.Duration("duration", d),
+		slog.Int("retries", retries),
+	)
+	if resp != nil && resp.Header.Get("RateLimit-Remaining") != "" {
+		attrs = append(attrs, slog.Int("ratelimit_remaining", resp.Meta.Ratelimit.Remaining))
+	}
+	zoneID, recordID := resourceIDs(path, r.URL.Query())
+	if zoneID != "" {
+		attrs = append(attrs, slog.String("zone_id", zoneID))
+	}
+	if recordID != "" {
+		attrs = append(attrs, slog.String("record_id", recordID))
+	}
+
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelError
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	c.logger.LogAttrs(r.Context(), level, "hdns: API call", attrs...)
+}
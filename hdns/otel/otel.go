@@ -0,0 +1,110 @@
+// Package otel provides an hdns.Tracer creating OpenTelemetry spans for the
+// operations of an hdns.Client.
+//
+// The spans are started from the context passed to the operations, so they
+// become part of the trace the caller is in:
+//
+//	client := hdns.NewClient(hdns.WithToken(token), hdns.WithTracer(otel.NewTracer()))
+package otel
+
+import (
+	"context"
+	"github.com/alxrem/hdns-go/hdns"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"sync"
+)
+
+const instrumentationName = "github.com/alxrem/hdns-go/hdns/otel"
+
+// Tracer is an hdns.Tracer starting an OpenTelemetry span for every
+// operation.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// An Option is used to configure a Tracer.
+type Option func(*config)
+
+type config struct {
+	provider trace.TracerProvider
+}
+
+// WithTracerProvider configures a Tracer to create spans with the given
+// provider instead of the global one.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// NewTracer creates a new Tracer.
+func NewTracer(options ...Option) *Tracer {
+	c := config{provider: otel.GetTracerProvider()}
+	for _, option := range options {
+		option(&c)
+	}
+	return &Tracer{
+		tracer: c.provider.Tracer(instrumentationName, trace.WithInstrumentationVersion(hdns.Version)),
+	}
+}
+
+// StartOperation implements hdns.Tracer.
+func (t *Tracer) StartOperation(ctx context.Context, name string, attrs hdns.OperationAttributes) (context.Context, hdns.OperationSpan) {
+	ctx, span := t.tracer.Start(ctx, "hdns."+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(operationAttributes(attrs)...),
+	)
+	return ctx, &operationSpan{span: span}
+}
+
+func operationAttributes(attrs hdns.OperationAttributes) []attribute.KeyValue {
+	var kvs []attribute.KeyValue
+	if attrs.ZoneID != "" {
+		kvs = append(kvs, attribute.String("hdns.zone.id", attrs.ZoneID))
+	}
+	if attrs.ZoneName != "" {
+		kvs = append(kvs, attribute.String("hdns.zone.name", attrs.ZoneName))
+	}
+	if attrs.RecordID != "" {
+		kvs = append(kvs, attribute.String("hdns.record.id", attrs.RecordID))
+	}
+	if attrs.RecordType != "" {
+		kvs = append(kvs, attribute.String("hdns.record.type", attrs.RecordType))
+	}
+	if attrs.RecordCount > 0 {
+		kvs = append(kvs, attribute.Int("hdns.record.count", attrs.RecordCount))
+	}
+	return kvs
+}
+
+type operationSpan struct {
+	span trace.Span
+
+	mu      sync.Mutex
+	retries int
+}
+
+// ObserveCall implements hdns.OperationSpan.
+func (s *operationSpan) ObserveCall(resp *hdns.Response, retries int, _ error) {
+	s.mu.Lock()
+	s.retries += retries
+	total := s.retries
+	s.mu.Unlock()
+
+	s.span.SetAttributes(attribute.Int("hdns.retries", total))
+	if resp != nil {
+		s.span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	}
+}
+
+// End implements hdns.OperationSpan.
+func (s *operationSpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
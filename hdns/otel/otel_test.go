@@ -0,0 +1,82 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"github.com/alxrem/hdns-go/hdns"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTracer(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zones", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"zones":[{"id":"1","name":"example.com"}],"meta":{"pagination":{"page":1,"per_page":100,"last_page":1,"total_entries":1}}}`)
+	})
+	mux.HandleFunc("/zones/example.com", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"code":404,"message":"zone not found"}}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := hdns.NewClient(
+		hdns.WithEndpoint(server.URL),
+		hdns.WithToken("token"),
+		hdns.WithTracer(NewTracer(WithTracerProvider(provider))),
+	)
+
+	zone, _, err := client.Zone.Get(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zone == nil || zone.ID != "1" {
+		t.Fatalf("unexpected zone: %v", zone)
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	get, ok := spans["hdns.ZoneClient.Get"]
+	if !ok {
+		t.Fatalf("no span for ZoneClient.Get in %v", spans)
+	}
+	if get.SpanKind().String() != "client" {
+		t.Errorf("unexpected span kind: %v", get.SpanKind())
+	}
+	if get.Status().Code != codes.Unset {
+		t.Errorf("unexpected status: %v", get.Status())
+	}
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range get.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if v := attrs["hdns.zone.name"].AsString(); v != "example.com" {
+		t.Errorf("unexpected hdns.zone.name: %q", v)
+	}
+	// The API calls of the nested operations are observed by the outer span.
+	if v := attrs["http.response.status_code"].AsInt64(); v != http.StatusOK {
+		t.Errorf("unexpected http.response.status_code: %d", v)
+	}
+	if _, ok := attrs["hdns.retries"]; !ok {
+		t.Error("no hdns.retries attribute")
+	}
+
+	getByID, ok := spans["hdns.ZoneClient.GetByID"]
+	if !ok {
+		t.Fatalf("no span for ZoneClient.GetByID in %v", spans)
+	}
+	if getByID.Parent().SpanID() != get.SpanContext().SpanID() {
+		t.Error("ZoneClient.GetByID span is not a child of the ZoneClient.Get span")
+	}
+}
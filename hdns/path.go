@@ -0,0 +1,48 @@
+package hdns
+
+import (
+	"net/url"
+	"strings"
+)
+
+// apiPath returns the path of u relative to the endpoint of the Client.
+func (c *Client) apiPath(u *url.URL) string {
+	if endpoint, err := url.Parse(c.endpoint); err == nil {
+		return "/" + strings.TrimPrefix(strings.TrimPrefix(u.Path, endpoint.Path), "/")
+	}
+	return u.Path
+}
+
+// resourceIDs returns the IDs of the zone and the record a request with the
+// given API path and query is about, as far as they are known.
+func resourceIDs(path string, query url.Values) (zoneID, recordID string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) >= 2 && !isPathKeyword(segments[1]) {
+		switch segments[0] {
+		case "zones":
+			zoneID = segments[1]
+		case "records":
+			recordID = segments[1]
+		}
+	}
+	if zoneID == "" {
+		zoneID = query.Get("zone_id")
+	}
+	return zoneID, recordID
+}
+
+// isPathKeyword returns whether the path segment following a resource name
+// is a fixed part of the API rather than an ID.
+func isPathKeyword(segment string) bool {
+	return segment == "bulk" || segment == "file"
+}
+
+// normalizeEndpoint returns the API path with IDs replaced by a placeholder,
+// e.g. /records/{id} for /records/abc.
+func normalizeEndpoint(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) >= 2 && !isPathKeyword(segments[1]) {
+		segments[1] = "{id}"
+	}
+	return "/" + strings.Join(segments, "/")
+}
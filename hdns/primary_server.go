@@ -0,0 +1,152 @@
+package hdns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/alxrem/hdns-go/hdns/schema"
+	"net/url"
+)
+
+// PrimaryServer represents a primary name server of a secondary zone.
+type PrimaryServer struct {
+	ID       string      `json:"id" yaml:"id"`
+	Address  string      `json:"address" yaml:"address"`
+	Port     int         `json:"port" yaml:"port"`
+	ZoneID   string      `json:"zone_id" yaml:"zone_id"`
+	Created  schema.Time `json:"created" yaml:"created"`
+	Modified schema.Time `json:"modified" yaml:"modified"`
+}
+
+// PrimaryServerClient is a client for the primary servers API.
+type PrimaryServerClient struct {
+	client *Client
+}
+
+// GetByID retrieves a PrimaryServer by its ID. If the primary server does
+// not exist, nil is returned.
+func (c *PrimaryServerClient) GetByID(ctx context.Context, id string) (*PrimaryServer, *Response, error) {
+	req, err := c.client.NewRequest(ctx, "GET", fmt.Sprintf("/primary_servers/%s", id), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var body schema.PrimaryServerGetResponse
+	resp, err := c.client.Do(req, &body)
+	if err != nil {
+		if IsError(err, ErrorCodeNotFound) {
+			return nil, resp, nil
+		}
+		return nil, resp, err
+	}
+	return PrimaryServerFromSchema(body.PrimaryServer), resp, nil
+}
+
+// PrimaryServerListOpts specifies options for listing primary servers.
+type PrimaryServerListOpts struct {
+	ZoneID string
+}
+
+func (l PrimaryServerListOpts) values() url.Values {
+	vals := url.Values{}
+	if l.ZoneID != "" {
+		vals.Add("zone_id", l.ZoneID)
+	}
+	return vals
+}
+
+// List returns a list of primary servers, optionally filtered by zone.
+func (c *PrimaryServerClient) List(ctx context.Context, opts PrimaryServerListOpts) ([]*PrimaryServer, *Response, error) {
+	path := "/primary_servers"
+	if vals := opts.values(); len(vals) > 0 {
+		path += "?" + vals.Encode()
+	}
+	req, err := c.client.NewRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var body schema.PrimaryServerListResponse
+	resp, err := c.client.Do(req, &body)
+	if err != nil {
+		return nil, resp, err
+	}
+	return PrimaryServersFromSchema(body.PrimaryServers), resp, nil
+}
+
+// PrimaryServerCreateOpts specifies parameters for creating a PrimaryServer.
+type PrimaryServerCreateOpts struct {
+	Address string
+	Port    int
+	ZoneID  string
+}
+
+// Create creates a PrimaryServer.
+func (c *PrimaryServerClient) Create(ctx context.Context, opts PrimaryServerCreateOpts) (*PrimaryServer, *Response, error) {
+	reqBody := schema.PrimaryServerCreateRequest{
+		Address: opts.Address,
+		Port:    opts.Port,
+		ZoneID:  opts.ZoneID,
+	}
+	reqBodyData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := c.client.NewRequest(ctx, "POST", "/primary_servers", bytes.NewReader(reqBodyData))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var respBody schema.PrimaryServerCreateResponse
+	resp, err := c.client.Do(req, &respBody)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return PrimaryServerFromSchema(respBody.PrimaryServer), resp, nil
+}
+
+// PrimaryServerUpdateOpts specifies parameters for updating a PrimaryServer.
+type PrimaryServerUpdateOpts struct {
+	Address string
+	Port    int
+	ZoneID  string
+}
+
+// Update updates a PrimaryServer.
+func (c *PrimaryServerClient) Update(ctx context.Context, id string, opts PrimaryServerUpdateOpts) (*PrimaryServer, *Response, error) {
+	reqBody := schema.PrimaryServerUpdateRequest{
+		Address: opts.Address,
+		Port:    opts.Port,
+		ZoneID:  opts.ZoneID,
+	}
+	reqBodyData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	path := fmt.Sprintf("/primary_servers/%s", id)
+	req, err := c.client.NewRequest(ctx, "PUT", path, bytes.NewReader(reqBodyData))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var respBody schema.PrimaryServerUpdateResponse
+	resp, err := c.client.Do(req, &respBody)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return PrimaryServerFromSchema(respBody.PrimaryServer), resp, nil
+}
+
+// Delete deletes a PrimaryServer.
+func (c *PrimaryServerClient) Delete(ctx context.Context, id string) (*Response, error) {
+	req, err := c.client.NewRequest(ctx, "DELETE", fmt.Sprintf("/primary_servers/%s", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.client.Do(req, nil)
+}
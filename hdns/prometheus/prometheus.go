@@ -0,0 +1,91 @@
+// Package prometheus provides an hdns.Instrumenter exposing metrics of the
+// API calls made by an hdns.Client as Prometheus collectors.
+package prometheus
+
+import (
+	"github.com/alxrem/hdns-go/hdns"
+	"github.com/prometheus/client_golang/prometheus"
+	"strconv"
+	"time"
+)
+
+// Instrumenter is an hdns.Instrumenter collecting Prometheus metrics. It
+// implements prometheus.Collector, so it can be registered directly.
+type Instrumenter struct {
+	requests           *prometheus.CounterVec
+	requestDuration    *prometheus.HistogramVec
+	retries            *prometheus.CounterVec
+	ratelimitLimit     prometheus.Gauge
+	ratelimitRemaining prometheus.Gauge
+}
+
+// NewInstrumenter creates an Instrumenter whose metric names are prefixed
+// with "hdns_".
+func NewInstrumenter() *Instrumenter {
+	return &Instrumenter{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "hdns_requests_total",
+			Help: "Number of HTTP requests sent to the Hetzner DNS API.",
+		}, []string{"method", "endpoint", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "hdns_request_duration_seconds",
+			Help:    "Duration of HTTP requests sent to the Hetzner DNS API.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "endpoint"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "hdns_retries_total",
+			Help: "Number of retried HTTP requests to the Hetzner DNS API.",
+		}, []string{"method", "endpoint", "reason"}),
+		ratelimitLimit: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "hdns_ratelimit_limit",
+			Help: "Rate limit of the Hetzner DNS API as reported by the last response.",
+		}),
+		ratelimitRemaining: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "hdns_ratelimit_remaining",
+			Help: "Remaining requests of the rate limit as reported by the last response.",
+		}),
+	}
+}
+
+// ObserveRequest implements hdns.Instrumenter.
+func (i *Instrumenter) ObserveRequest(method, endpoint string, statusCode int, d time.Duration) {
+	code := "error"
+	if statusCode != 0 {
+		code = strconv.Itoa(statusCode)
+	}
+	i.requests.WithLabelValues(method, endpoint, code).Inc()
+	i.requestDuration.WithLabelValues(method, endpoint).Observe(d.Seconds())
+}
+
+// ObserveRetry implements hdns.Instrumenter.
+func (i *Instrumenter) ObserveRetry(method, endpoint string, rateLimited bool) {
+	reason := "error"
+	if rateLimited {
+		reason = "ratelimit"
+	}
+	i.retries.WithLabelValues(method, endpoint, reason).Inc()
+}
+
+// ObserveRateLimit implements hdns.Instrumenter.
+func (i *Instrumenter) ObserveRateLimit(r hdns.Ratelimit) {
+	i.ratelimitLimit.Set(float64(r.Limit))
+	i.ratelimitRemaining.Set(float64(r.Remaining))
+}
+
+// Describe implements prometheus.Collector.
+func (i *Instrumenter) Describe(ch chan<- *prometheus.Desc) {
+	i.requests.Describe(ch)
+	i.requestDuration.Describe(ch)
+	i.retries.Describe(ch)
+	i.ratelimitLimit.Describe(ch)
+	i.ratelimitRemaining.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (i *Instrumenter) Collect(ch chan<- prometheus.Metric) {
+	i.requests.Collect(ch)
+	i.requestDuration.Collect(ch)
+	i.retries.Collect(ch)
+	i.ratelimitLimit.Collect(ch)
+	i.ratelimitRemaining.Collect(ch)
+}
@@ -0,0 +1,51 @@
+package prometheus
+
+import (
+	"context"
+	"github.com/alxrem/hdns-go/hdns"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestInstrumenter(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("RateLimit-Limit", "10")
+		w.Header().Set("RateLimit-Remaining", "3")
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"record":{"id":"abc"}}`))
+	}))
+	defer server.Close()
+
+	instrumenter := NewInstrumenter()
+	client := hdns.NewClient(
+		hdns.WithEndpoint(server.URL),
+		hdns.WithInstrumenter(instrumenter),
+		hdns.WithBackoffFunc(hdns.ConstantBackoff(time.Millisecond)),
+		hdns.WithRateLimitStrategy(hdns.BackoffRateLimitStrategy),
+	)
+	if _, _, err := client.Record.GetByID(context.Background(), "abc"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if v := testutil.ToFloat64(instrumenter.requests.WithLabelValues("GET", "/records/{id}", "429")); v != 1 {
+		t.Errorf("expected 1 rate limited request, got %v", v)
+	}
+	if v := testutil.ToFloat64(instrumenter.requests.WithLabelValues("GET", "/records/{id}", "200")); v != 1 {
+		t.Errorf("expected 1 successful request, got %v", v)
+	}
+	if v := testutil.ToFloat64(instrumenter.retries.WithLabelValues("GET", "/records/{id}", "ratelimit")); v != 1 {
+		t.Errorf("expected 1 retry, got %v", v)
+	}
+	if v := testutil.ToFloat64(instrumenter.ratelimitRemaining); v != 3 {
+		t.Errorf("expected 3 remaining requests, got %v", v)
+	}
+}
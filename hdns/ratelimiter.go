@@ -0,0 +1,131 @@
+package hdns
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultRateLimitPeriod is the assumed duration between two resets of the
+// rate limit as long as it could not be learned from the responses.
+const defaultRateLimitPeriod = time.Second
+
+// RateLimiter throttles requests before the API starts to refuse them. It
+// learns the current rate limit from the RateLimit-* headers of the
+// responses and, once no requests remain, delays further requests until the
+// rate limit is reset. After a reset it lets pass at most as many requests
+// as the limit allows until the next response tells how the rate limit
+// continues. Responses lacking any of the RateLimit-* headers are ignored.
+// A RateLimiter is safe for concurrent use and may be shared by several
+// Clients using the same token.
+type RateLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	remaining int
+	reset     time.Time
+	// estimated reports whether reset was estimated from period rather
+	// than taken from a response.
+	estimated bool
+	// period is the shortest duration seen between two resets.
+	period time.Duration
+	// lastReset is the last reset time taken from a response.
+	lastReset time.Time
+}
+
+// NewRateLimiter creates a new RateLimiter. Until the first response is
+// seen, requests are not throttled.
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{}
+}
+
+// Wait blocks until a request may be sent. It returns the error of ctx if
+// ctx is done before.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		d := l.reserve(time.Now())
+		if d <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes one of the remaining requests. If none remain, it returns
+// how long to wait until the rate limit is reset.
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.reset.IsZero() {
+		// Nothing is known about the rate limit yet.
+		return 0
+	}
+	if !now.Before(l.reset) {
+		// The rate limit has been reset. Start counting down from the
+		// limit again until the next response tells the actual state.
+		period := l.period
+		if period <= 0 {
+			period = defaultRateLimitPeriod
+		}
+		periods := now.Sub(l.reset)/period + 1
+		l.reset = l.reset.Add(periods * period)
+		l.remaining = l.limit
+		l.estimated = true
+	}
+	if l.remaining > 0 {
+		l.remaining--
+		return 0
+	}
+	return l.reset.Sub(now)
+}
+
+// Update updates the RateLimiter with the rate limit information of a
+// response.
+func (l *RateLimiter) Update(r Ratelimit) {
+	l.update(time.Now(), r)
+}
+
+func (l *RateLimiter) update(now time.Time, r Ratelimit) {
+	if r.Reset.IsZero() || r.Limit <= 0 {
+		// The rate limit is unknown.
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if r.Reset.After(l.lastReset) {
+		if !l.lastReset.IsZero() {
+			if d := r.Reset.Sub(l.lastReset); l.period <= 0 || d < l.period {
+				l.period = d
+			}
+		}
+		l.lastReset = r.Reset
+	}
+	l.limit = r.Limit
+
+	switch {
+	case !r.Reset.After(now):
+		// Stale information of a response to a request sent before
+		// the last reset.
+	case l.reset.IsZero() || r.Reset.After(l.reset) && !l.estimated:
+		l.remaining = r.Remaining
+		l.reset = r.Reset
+	case l.estimated:
+		// Requests may have been let pass since the response was
+		// created, so keep the lower count.
+		if r.Remaining < l.remaining {
+			l.remaining = r.Remaining
+		}
+		l.reset = r.Reset
+		l.estimated = false
+	case r.Reset.Equal(l.reset) && r.Remaining < l.remaining:
+		l.remaining = r.Remaining
+	}
+}
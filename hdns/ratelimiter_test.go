@@ -0,0 +1,124 @@
+package hdns
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterNotThrottlingUnknownLimit(t *testing.T) {
+	l := NewRateLimiter()
+	for i := 0; i < 10; i++ {
+		if d := l.reserve(time.Now()); d != 0 {
+			t.Fatalf("expected no wait, got %s", d)
+		}
+	}
+}
+
+func TestRateLimiterWaitsForReset(t *testing.T) {
+	now := time.Now()
+	l := NewRateLimiter()
+	l.update(now, Ratelimit{Limit: 5, Remaining: 1, Reset: now.Add(time.Second)})
+
+	if d := l.reserve(now); d != 0 {
+		t.Fatalf("expected no wait, got %s", d)
+	}
+	if d := l.reserve(now); d != time.Second {
+		t.Fatalf("expected to wait 1s, got %s", d)
+	}
+	if d := l.reserve(now.Add(time.Second)); d != 0 {
+		t.Fatalf("expected no wait after reset, got %s", d)
+	}
+}
+
+func TestRateLimiterKeepsCountingAfterReset(t *testing.T) {
+	now := time.Now()
+	l := NewRateLimiter()
+	l.update(now, Ratelimit{Limit: 2, Remaining: 0, Reset: now.Add(time.Second)})
+
+	afterReset := now.Add(time.Second)
+	for i := 0; i < 2; i++ {
+		if d := l.reserve(afterReset); d != 0 {
+			t.Fatalf("request %d: expected no wait, got %s", i, d)
+		}
+	}
+	if d := l.reserve(afterReset); d <= 0 {
+		t.Fatal("expected to wait once the limit is used up after the reset")
+	}
+}
+
+func TestRateLimiterIgnoresStaleUpdates(t *testing.T) {
+	now := time.Now()
+	l := NewRateLimiter()
+	l.update(now, Ratelimit{Limit: 5, Remaining: 0, Reset: now.Add(2 * time.Second)})
+	l.update(now, Ratelimit{Limit: 5, Remaining: 5, Reset: now.Add(-time.Second)})
+	l.update(now, Ratelimit{Limit: 5, Remaining: 3, Reset: now.Add(2 * time.Second)})
+
+	if d := l.reserve(now); d != 2*time.Second {
+		t.Fatalf("expected to wait 2s, got %s", d)
+	}
+}
+
+func TestRateLimiterConcurrentWaitersAfterReset(t *testing.T) {
+	const limit = 2
+	l := NewRateLimiter()
+	reset := time.Now().Add(50 * time.Millisecond)
+	l.Update(Ratelimit{Limit: limit, Remaining: 0, Reset: reset})
+
+	ctx, cancel := context.WithDeadline(context.Background(), reset.Add(300*time.Millisecond))
+	defer cancel()
+
+	var passed int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.Wait(ctx); err == nil {
+				atomic.AddInt32(&passed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&passed); n == 0 || n > limit {
+		t.Fatalf("expected 1 to %d requests to pass, got %d", limit, n)
+	}
+}
+
+func TestRateLimiterIgnoresUnknownLimit(t *testing.T) {
+	now := time.Now()
+	l := NewRateLimiter()
+	l.update(now, Ratelimit{Reset: now.Add(time.Second)})
+
+	for _, at := range []time.Time{now, now.Add(time.Second), now.Add(5 * time.Second)} {
+		if d := l.reserve(at); d != 0 {
+			t.Fatalf("expected no wait, got %s", d)
+		}
+	}
+}
+
+func TestRateLimiterIgnoresIncompleteHeaders(t *testing.T) {
+	env := newTestEnv(WithRateLimiter(NewRateLimiter()))
+	defer env.Teardown()
+
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	env.Mux.HandleFunc("/zones/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("RateLimit-Limit", "1")
+		w.Header().Set("RateLimit-Reset", reset)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"zone":{"id":"1"}}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		if _, _, err := env.Client.Zone.GetByID(ctx, "1"); err != nil {
+			t.Fatalf("request %d: unexpected error: %s", i, err)
+		}
+	}
+}
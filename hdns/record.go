@@ -7,28 +7,42 @@ import (
 	"fmt"
 	"github.com/alxrem/hdns-go/hdns/schema"
 	"net/url"
+	"strings"
+	"sync"
 )
 
 type BaseRecord struct {
-	Name   string
-	TTL    int
-	Type   string
-	Value  string
-	ZoneID string
+	Name   string     `json:"name" yaml:"name"`
+	TTL    int        `json:"ttl" yaml:"ttl"`
+	Type   RecordType `json:"type" yaml:"type"`
+	Value  string     `json:"value" yaml:"value"`
+	ZoneID string     `json:"zone_id" yaml:"zone_id"`
 }
 
+// Record represents a DNS record. Record implements json.Marshaler, so a
+// struct embedding a Record is encoded as the Record alone; use a named
+// field instead.
 type Record struct {
 	BaseRecord
 	ID       string
 	Created  schema.Time
 	Modified schema.Time
+
+	// Extra holds the fields returned from the API which are not known
+	// to this library yet.
+	Extra map[string]json.RawMessage
+	// Raw holds the JSON object the Record was decoded from, if any.
+	Raw json.RawMessage
 }
 
 type RecordClient struct {
 	client *Client
 }
 
-func (c *RecordClient) GetByID(ctx context.Context, id string) (*Record, *Response, error) {
+func (c *RecordClient) GetByID(ctx context.Context, id string) (_ *Record, _ *Response, err error) {
+	ctx, end := c.client.startOperation(ctx, "RecordClient.GetByID", OperationAttributes{RecordID: id})
+	defer func() { end(err) }()
+
 	req, err := c.client.NewRequest(ctx, "GET", fmt.Sprintf("/records/%s", id), nil)
 	if err != nil {
 		return nil, nil, err
@@ -45,7 +59,7 @@ func (c *RecordClient) GetByID(ctx context.Context, id string) (*Record, *Respon
 	return RecordFromSchema(body.Record), resp, nil
 }
 
-// ActionListOpts specifies options for listing actions.
+// RecordListOpts specifies options for listing records.
 type RecordListOpts struct {
 	ListOpts
 	ZoneID string
@@ -59,8 +73,11 @@ func (l RecordListOpts) values() url.Values {
 	return vals
 }
 
-// List returns a list of actions for a specific page.
-func (c *RecordClient) List(ctx context.Context, opts RecordListOpts) ([]*Record, *Response, error) {
+// List returns a list of records for a specific page.
+func (c *RecordClient) List(ctx context.Context, opts RecordListOpts) (_ []*Record, _ *Response, err error) {
+	ctx, end := c.client.startOperation(ctx, "RecordClient.List", OperationAttributes{ZoneID: opts.ZoneID})
+	defer func() { end(err) }()
+
 	path := "/records?" + opts.values().Encode()
 	req, err := c.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
@@ -79,41 +96,118 @@ func (c *RecordClient) List(ctx context.Context, opts RecordListOpts) ([]*Record
 	return records, resp, nil
 }
 
+// All returns all records.
 func (c *RecordClient) All(ctx context.Context) ([]*Record, error) {
-	req, err := c.client.NewRequest(ctx, "GET", "/records", nil)
+	return c.AllWithOpts(ctx, RecordListOpts{ListOpts: ListOpts{PerPage: 100}})
+}
+
+// AllWithOpts returns all records matching the given options.
+func (c *RecordClient) AllWithOpts(ctx context.Context, opts RecordListOpts) (_ []*Record, err error) {
+	ctx, end := c.client.startOperation(ctx, "RecordClient.AllWithOpts", OperationAttributes{ZoneID: opts.ZoneID})
+	defer func() { end(err) }()
+
+	allRecords := []*Record{}
+
+	_, err = c.client.all(func(page int) (*Response, error) {
+		opts.Page = page
+		records, resp, err := c.List(ctx, opts)
+		if err != nil {
+			return resp, err
+		}
+		allRecords = append(allRecords, records...)
+		return resp, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	var body schema.RecordAllResponse
-	_, err = c.client.Do(req, &body)
+	return allRecords, nil
+}
+
+// Find returns all records of the zone with the given ID having the given
+// name and type, i.e. a whole RRset. The name may be "@" or empty for the
+// zone apex, relative to the zone or fully qualified, with or without a
+// trailing dot. Names and types are matched case-insensitively. If typ is
+// empty, records of all types are returned.
+func (c *RecordClient) Find(ctx context.Context, zoneID, name string, typ RecordType) (_ []*Record, err error) {
+	ctx, end := c.client.startOperation(ctx, "RecordClient.Find", OperationAttributes{ZoneID: zoneID, RecordType: string(typ)})
+	defer func() { end(err) }()
+
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if strings.Contains(name, ".") {
+		zone, _, err := c.client.Zone.GetByID(ctx, zoneID)
+		if err != nil {
+			return nil, err
+		}
+		if zone == nil {
+			return nil, fmt.Errorf("hdns: zone %s not found", zoneID)
+		}
+		name = relativeRecordName(name, zone.Name)
+	}
+	name = apexRecordName(name)
+
+	records, err := c.AllWithOpts(ctx, RecordListOpts{ListOpts: ListOpts{PerPage: 100}, ZoneID: zoneID})
 	if err != nil {
 		return nil, err
 	}
 
-	records := make([]*Record, 0, len(body.Records))
-	for _, z := range body.Records {
-		records = append(records, RecordFromSchema(z))
+	found := []*Record{}
+	for _, record := range records {
+		if typ != "" && !strings.EqualFold(string(record.Type), string(typ)) {
+			continue
+		}
+		if apexRecordName(strings.ToLower(record.Name)) != name {
+			continue
+		}
+		found = append(found, record)
 	}
+	return found, nil
+}
 
-	return records, nil
+// relativeRecordName returns the lower-cased name relative to the zone.
+// Names outside of the zone are returned unchanged.
+func relativeRecordName(name, zoneName string) string {
+	zoneName = normalizeZoneName(zoneName)
+	if name == zoneName {
+		return "@"
+	}
+	if strings.HasSuffix(name, "."+zoneName) {
+		return strings.TrimSuffix(name, "."+zoneName)
+	}
+	return name
+}
+
+func apexRecordName(name string) string {
+	if name == "" {
+		return "@"
+	}
+	return name
 }
 
 // RecordCreateOpts specifies parameters for creating a Record.
 type RecordCreateOpts struct {
 	Name   string
 	TTL    int
-	Type   string
+	Type   RecordType
 	Value  string
 	ZoneID string
 }
 
-// Create creates a Record.
-func (c *RecordClient) Create(ctx context.Context, opts RecordCreateOpts) (*Record, *Response, error) {
+// Create creates a Record. If the type of the Record is not supported, an
+// *InvalidRecordTypeError is returned without calling the API. If the API
+// rejects the input, a *ValidationError is returned.
+func (c *RecordClient) Create(ctx context.Context, opts RecordCreateOpts) (_ *Record, _ *Response, err error) {
+	ctx, end := c.client.startOperation(ctx, "RecordClient.Create", OperationAttributes{ZoneID: opts.ZoneID, RecordType: string(opts.Type)})
+	defer func() { end(err) }()
+
+	if err := validateRecordType(opts.Type); err != nil {
+		return nil, nil, err
+	}
+
 	reqBody := schema.RecordCreateRequest{
 		Name:   opts.Name,
 		TTL:    opts.TTL,
-		Type:   opts.Type,
+		Type:   string(opts.Type),
 		Value:  opts.Value,
 		ZoneID: opts.ZoneID,
 	}
@@ -136,7 +230,10 @@ func (c *RecordClient) Create(ctx context.Context, opts RecordCreateOpts) (*Reco
 	return RecordFromSchema(respBody.Record), resp, nil
 }
 
-func (c *RecordClient) Delete(ctx context.Context, id string) (*Response, error) {
+func (c *RecordClient) Delete(ctx context.Context, id string) (_ *Response, err error) {
+	ctx, end := c.client.startOperation(ctx, "RecordClient.Delete", OperationAttributes{RecordID: id})
+	defer func() { end(err) }()
+
 	req, err := c.client.NewRequest(ctx, "DELETE", fmt.Sprintf("/records/%s", id), nil)
 	if err != nil {
 		return nil, err
@@ -148,17 +245,26 @@ func (c *RecordClient) Delete(ctx context.Context, id string) (*Response, error)
 type RecordUpdateOpts struct {
 	Name   string
 	TTL    int
-	Type   string
+	Type   RecordType
 	Value  string
 	ZoneID string
 }
 
-// Update updates a Record.
-func (c *RecordClient) Update(ctx context.Context, id string, opts RecordUpdateOpts) (*Record, *Response, error) {
+// Update updates a Record. If the type of the Record is not supported, an
+// *InvalidRecordTypeError is returned without calling the API. If the API
+// rejects the input, a *ValidationError is returned.
+func (c *RecordClient) Update(ctx context.Context, id string, opts RecordUpdateOpts) (_ *Record, _ *Response, err error) {
+	ctx, end := c.client.startOperation(ctx, "RecordClient.Update", OperationAttributes{ZoneID: opts.ZoneID, RecordID: id, RecordType: string(opts.Type)})
+	defer func() { end(err) }()
+
+	if err := validateRecordType(opts.Type); err != nil {
+		return nil, nil, err
+	}
+
 	reqBody := schema.RecordUpdateRequest{
 		Name:   opts.Name,
 		TTL:    opts.TTL,
-		Type:   opts.Type,
+		Type:   string(opts.Type),
 		Value:  opts.Value,
 		ZoneID: opts.ZoneID,
 	}
@@ -193,17 +299,31 @@ type RecordBulkCreateResult struct {
 	ValidRecords   []*BaseRecord
 }
 
-// BulkCreate creates several Records at once.
-func (c *RecordClient) BulkCreate(ctx context.Context, opts RecordBulkCreateOpts) (RecordBulkCreateResult, *Response, error) {
+// BulkCreate creates several Records at once. If the type of any Record is
+// not supported, an *InvalidRecordTypeError is returned without calling the
+// API. If the API rejects the input, a *ValidationError is returned.
+func (c *RecordClient) BulkCreate(ctx context.Context, opts RecordBulkCreateOpts) (_ RecordBulkCreateResult, _ *Response, err error) {
+	var zoneIDs []string
+	var types []RecordType
+	for _, record := range opts.Records {
+		zoneIDs = append(zoneIDs, record.ZoneID)
+		types = append(types, record.Type)
+	}
+	ctx, end := c.client.startOperation(ctx, "RecordClient.BulkCreate", bulkOperationAttributes(zoneIDs, types, len(opts.Records)))
+	defer func() { end(err) }()
+
 	reqBody := schema.RecordBulkCreateRequest{
 		Records: []schema.RecordCreateRequest{},
 	}
 
 	for _, record := range opts.Records {
+		if err := validateRecordType(record.Type); err != nil {
+			return RecordBulkCreateResult{}, nil, err
+		}
 		reqRecordBody := schema.RecordCreateRequest{
 			Name:   record.Name,
 			TTL:    record.TTL,
-			Type:   record.Type,
+			Type:   string(record.Type),
 			Value:  record.Value,
 			ZoneID: record.ZoneID,
 		}
@@ -235,27 +355,54 @@ func (c *RecordClient) BulkCreate(ctx context.Context, opts RecordBulkCreateOpts
 	return result, resp, nil
 }
 
-// RecordBulkUpdateOpts specifies parameters for creating several Records at once.
+// RecordBulkUpdateEntry specifies parameters for updating a single Record
+// as part of a bulk update.
+type RecordBulkUpdateEntry struct {
+	ID string
+	RecordUpdateOpts
+}
+
+// RecordBulkUpdateOpts specifies parameters for updating several Records at once.
 type RecordBulkUpdateOpts struct {
-	Records []RecordUpdateOpts
+	Records []RecordBulkUpdateEntry
 }
 
 type RecordBulkUpdateResult struct {
+	// FailedRecords are the records the API failed to update.
 	FailedRecords []*BaseRecord
+	// FailedEntries are the entries of RecordBulkUpdateOpts.Records matching
+	// FailedRecords by name, TTL, type, value and zone ID. Failed records
+	// that can't be matched to an entry are only reported in FailedRecords.
+	FailedEntries []RecordBulkUpdateEntry
 	Records       []*Record
 }
 
-// BulkUpdate updates several Records at once.
-func (c *RecordClient) BulkUpdate(ctx context.Context, opts RecordBulkUpdateOpts) (RecordBulkUpdateResult, *Response, error) {
+// BulkUpdate updates several Records at once. If the type of any Record is
+// not supported, an *InvalidRecordTypeError is returned without calling the
+// API. If the API rejects the input, a *ValidationError is returned.
+func (c *RecordClient) BulkUpdate(ctx context.Context, opts RecordBulkUpdateOpts) (_ RecordBulkUpdateResult, _ *Response, err error) {
+	var zoneIDs []string
+	var types []RecordType
+	for _, record := range opts.Records {
+		zoneIDs = append(zoneIDs, record.ZoneID)
+		types = append(types, record.Type)
+	}
+	ctx, end := c.client.startOperation(ctx, "RecordClient.BulkUpdate", bulkOperationAttributes(zoneIDs, types, len(opts.Records)))
+	defer func() { end(err) }()
+
 	reqBody := schema.RecordBulkUpdateRequest{
-		Records: []schema.RecordUpdateRequest{},
+		Records: []schema.RecordBulkUpdateRecord{},
 	}
 
 	for _, record := range opts.Records {
-		reqRecordBody := schema.RecordUpdateRequest{
+		if err := validateRecordType(record.Type); err != nil {
+			return RecordBulkUpdateResult{}, nil, err
+		}
+		reqRecordBody := schema.RecordBulkUpdateRecord{
+			ID:     record.ID,
 			Name:   record.Name,
 			TTL:    record.TTL,
-			Type:   record.Type,
+			Type:   string(record.Type),
 			Value:  record.Value,
 			ZoneID: record.ZoneID,
 		}
@@ -267,7 +414,7 @@ func (c *RecordClient) BulkUpdate(ctx context.Context, opts RecordBulkUpdateOpts
 		return RecordBulkUpdateResult{}, nil, err
 	}
 
-	req, err := c.client.NewRequest(ctx, "POST", "/records/bulk", bytes.NewReader(reqBodyData))
+	req, err := c.client.NewRequest(ctx, "PUT", "/records/bulk", bytes.NewReader(reqBodyData))
 	if err != nil {
 		return RecordBulkUpdateResult{}, nil, err
 	}
@@ -282,6 +429,113 @@ func (c *RecordClient) BulkUpdate(ctx context.Context, opts RecordBulkUpdateOpts
 		FailedRecords: BaseRecordsFromSchema(respBody.FailedRecords),
 		Records:       RecordsFromSchema(respBody.Records),
 	}
+	result.FailedEntries = matchFailedEntries(opts.Records, result.FailedRecords)
 
 	return result, resp, nil
 }
+
+// matchFailedEntries returns the entries corresponding to the failed records.
+// The entries are matched by name, TTL, type, value and zone ID. Every entry
+// is matched at most once, so duplicated entries are reported as often as
+// the API reports them as failed.
+func matchFailedEntries(entries []RecordBulkUpdateEntry, failed []*BaseRecord) []RecordBulkUpdateEntry {
+	var matched []RecordBulkUpdateEntry
+	used := make([]bool, len(entries))
+	for _, f := range failed {
+		for i, e := range entries {
+			if used[i] {
+				continue
+			}
+			if e.Name == f.Name && e.TTL == f.TTL && e.Type == f.Type && e.Value == f.Value && e.ZoneID == f.ZoneID {
+				used[i] = true
+				matched = append(matched, e)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// RecordBulkDeleteOpts specifies parameters for deleting several Records at once.
+type RecordBulkDeleteOpts struct {
+	// Concurrency is the maximum number of concurrent delete requests.
+	// Defaults to 5.
+	Concurrency int
+}
+
+// RecordBulkDeleteResult is the result of deleting a single Record as part
+// of a bulk delete.
+type RecordBulkDeleteResult struct {
+	ID       string
+	Response *Response
+	Err      error
+}
+
+// RecordBulkDeleteError is returned by BulkDelete if deleting any of the
+// Records failed.
+type RecordBulkDeleteError struct {
+	Failed []RecordBulkDeleteResult
+}
+
+func (e *RecordBulkDeleteError) Error() string {
+	msgs := make([]string, 0, len(e.Failed))
+	for _, f := range e.Failed {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", f.ID, f.Err))
+	}
+	return fmt.Sprintf("hdns: failed to delete %d records: %s", len(e.Failed), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of the failed deletions.
+func (e *RecordBulkDeleteError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for _, f := range e.Failed {
+		errs = append(errs, f.Err)
+	}
+	return errs
+}
+
+// BulkDelete deletes several Records at once. The API has no bulk delete
+// operation, so the Records are deleted by concurrent calls of Delete, which
+// back off when the rate limit is exceeded. The results are returned in the
+// order of ids. If any deletion fails, a *RecordBulkDeleteError is returned
+// along with the results.
+func (c *RecordClient) BulkDelete(ctx context.Context, ids []string, opts RecordBulkDeleteOpts) (_ []RecordBulkDeleteResult, err error) {
+	ctx, end := c.client.startOperation(ctx, "RecordClient.BulkDelete", bulkOperationAttributes(nil, nil, len(ids)))
+	defer func() { end(err) }()
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 5
+	}
+
+	results := make([]RecordBulkDeleteResult, len(ids))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		results[i].ID = id
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i].Response, results[i].Err = c.Delete(ctx, id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	var failed []RecordBulkDeleteResult
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	if len(failed) > 0 {
+		return results, &RecordBulkDeleteError{Failed: failed}
+	}
+	return results, nil
+}
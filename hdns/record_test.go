@@ -0,0 +1,206 @@
+package hdns
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRecordClientFind(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	env.Mux.HandleFunc("/zones/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"zone":{"id":"1","name":"Example.com"}}`))
+	})
+	env.Mux.HandleFunc("/records", func(w http.ResponseWriter, r *http.Request) {
+		if zoneID := r.URL.Query().Get("zone_id"); zoneID != "1" {
+			t.Errorf("unexpected zone ID: %q", zoneID)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"records":[
+			{"id":"apex-a","name":"@","type":"A","zone_id":"1"},
+			{"id":"apex-mx","name":"@","type":"MX","zone_id":"1"},
+			{"id":"www","name":"www","type":"A","zone_id":"1"},
+			{"id":"acme","name":"_acme.WWW","type":"TXT","zone_id":"1"}
+		]}`))
+	})
+
+	for _, tt := range []struct {
+		name     string
+		typ      RecordType
+		expected []string
+	}{
+		{"@", RecordTypeA, []string{"apex-a"}},
+		{"", RecordTypeA, []string{"apex-a"}},
+		{"@", "", []string{"apex-a", "apex-mx"}},
+		{"example.com", RecordTypeA, []string{"apex-a"}},
+		{"example.com.", RecordTypeA, []string{"apex-a"}},
+		{"www", RecordTypeA, []string{"www"}},
+		{"WWW", "a", []string{"www"}},
+		{"www.example.com", RecordTypeA, []string{"www"}},
+		{"WWW.Example.COM.", RecordTypeA, []string{"www"}},
+		{"_acme.www", RecordTypeTXT, []string{"acme"}},
+		{"_acme.www.example.com.", RecordTypeTXT, []string{"acme"}},
+		{"www.example.org", RecordTypeA, nil},
+		{"mail", RecordTypeA, nil},
+	} {
+		records, err := env.Client.Record.Find(context.Background(), "1", tt.name, tt.typ)
+		if err != nil {
+			t.Fatalf("%q %s: unexpected error: %s", tt.name, tt.typ, err)
+		}
+		var ids []string
+		for _, record := range records {
+			ids = append(ids, record.ID)
+		}
+		if len(ids) != len(tt.expected) {
+			t.Errorf("%q %s: expected %v, got %v", tt.name, tt.typ, tt.expected, ids)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tt.expected[i] {
+				t.Errorf("%q %s: expected %v, got %v", tt.name, tt.typ, tt.expected, ids)
+				break
+			}
+		}
+	}
+}
+
+func TestRelativeRecordName(t *testing.T) {
+	for _, tt := range []struct {
+		name, zoneName, expected string
+	}{
+		{"example.com", "example.com", "@"},
+		{"example.com", "Example.com.", "@"},
+		{"www.example.com", "example.com", "www"},
+		{"_acme.www.example.com", "example.com", "_acme.www"},
+		{"_acme.www", "example.com", "_acme.www"},
+		{"www.example.org", "example.com", "www.example.org"},
+		{"wwwexample.com", "example.com", "wwwexample.com"},
+	} {
+		if name := relativeRecordName(tt.name, tt.zoneName); name != tt.expected {
+			t.Errorf("relativeRecordName(%q, %q): expected %q, got %q", tt.name, tt.zoneName, tt.expected, name)
+		}
+	}
+}
+
+func TestMatchFailedEntries(t *testing.T) {
+	entry := func(id string, ttl int) RecordBulkUpdateEntry {
+		return RecordBulkUpdateEntry{
+			ID:               id,
+			RecordUpdateOpts: RecordUpdateOpts{Name: "www", TTL: ttl, Type: RecordTypeA, Value: "192.0.2.1", ZoneID: "1"},
+		}
+	}
+	failed := func(ttl int) *BaseRecord {
+		return &BaseRecord{Name: "www", TTL: ttl, Type: RecordTypeA, Value: "192.0.2.1", ZoneID: "1"}
+	}
+	unmatched := &BaseRecord{Name: "mail", TTL: 60, Type: RecordTypeA, Value: "192.0.2.2", ZoneID: "1"}
+
+	for _, tt := range []struct {
+		name     string
+		entries  []RecordBulkUpdateEntry
+		failed   []*BaseRecord
+		expected []string
+	}{
+		{"none failed", []RecordBulkUpdateEntry{entry("1", 60)}, nil, nil},
+		{"duplicate failed once", []RecordBulkUpdateEntry{entry("1", 60), entry("2", 60)}, []*BaseRecord{failed(60)}, []string{"1"}},
+		{"duplicate failed twice", []RecordBulkUpdateEntry{entry("1", 60), entry("2", 60)}, []*BaseRecord{failed(60), failed(60)}, []string{"1", "2"}},
+		{"distinct TTL", []RecordBulkUpdateEntry{entry("1", 60), entry("2", 120)}, []*BaseRecord{failed(120)}, []string{"2"}},
+		{"unmatched", []RecordBulkUpdateEntry{entry("1", 60)}, []*BaseRecord{unmatched, failed(60)}, []string{"1"}},
+		{"more failed than entries", []RecordBulkUpdateEntry{entry("1", 60)}, []*BaseRecord{failed(60), failed(60)}, []string{"1"}},
+	} {
+		var ids []string
+		for _, e := range matchFailedEntries(tt.entries, tt.failed) {
+			ids = append(ids, e.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, ids)
+		}
+	}
+}
+
+func TestRecordClientBulkDelete(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	env.Mux.HandleFunc("/records/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("unexpected method: %s", r.Method)
+		}
+		if strings.HasPrefix(r.URL.Path, "/records/missing") {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":404,"message":"record not found"}}`))
+		}
+	})
+
+	ids := []string{"1", "missing-1", "2", "missing-2", "3"}
+	results, err := env.Client.Record.BulkDelete(context.Background(), ids, RecordBulkDeleteOpts{Concurrency: 2})
+	if len(results) != len(ids) {
+		t.Fatalf("expected %d results, got %d", len(ids), len(results))
+	}
+	for i, result := range results {
+		if result.ID != ids[i] {
+			t.Errorf("result %d: expected ID %s, got %s", i, ids[i], result.ID)
+		}
+		if missing := strings.HasPrefix(result.ID, "missing"); missing != (result.Err != nil) {
+			t.Errorf("result %d: unexpected error: %v", i, result.Err)
+		}
+	}
+
+	var bulkErr *RecordBulkDeleteError
+	if !errors.As(err, &bulkErr) {
+		t.Fatalf("expected *RecordBulkDeleteError, got %v", err)
+	}
+	if len(bulkErr.Failed) != 2 || bulkErr.Failed[0].ID != "missing-1" || bulkErr.Failed[1].ID != "missing-2" {
+		t.Errorf("unexpected failed deletions: %+v", bulkErr.Failed)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected error to match ErrNotFound, got %v", err)
+	}
+}
+
+func TestRecordClientBulkDeleteCanceled(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	deleted := map[string]bool{}
+	env.Mux.HandleFunc("/records/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/records/")
+		mu.Lock()
+		deleted[id] = true
+		mu.Unlock()
+		if id == "2" {
+			cancel()
+		}
+	})
+
+	ids := []string{"1", "2", "3", "4"}
+	results, err := env.Client.Record.BulkDelete(ctx, ids, RecordBulkDeleteOpts{Concurrency: 1})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled, got %v", err)
+	}
+	if results[0].Err != nil {
+		t.Errorf("unexpected error deleting the first record: %s", results[0].Err)
+	}
+	for _, result := range results[2:] {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("%s: expected context canceled, got %v", result.ID, result.Err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if deleted["3"] || deleted["4"] {
+		t.Errorf("records were deleted after the cancellation: %v", deleted)
+	}
+}
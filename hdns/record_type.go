@@ -0,0 +1,71 @@
+package hdns
+
+import "fmt"
+
+// RecordType is the type of a Record.
+type RecordType string
+
+// Record types supported by the API.
+const (
+	RecordTypeA     RecordType = "A"
+	RecordTypeAAAA  RecordType = "AAAA"
+	RecordTypeNS    RecordType = "NS"
+	RecordTypeMX    RecordType = "MX"
+	RecordTypeCNAME RecordType = "CNAME"
+	RecordTypeRP    RecordType = "RP"
+	RecordTypeTXT   RecordType = "TXT"
+	RecordTypeSOA   RecordType = "SOA"
+	RecordTypeHINFO RecordType = "HINFO"
+	RecordTypeSRV   RecordType = "SRV"
+	RecordTypeDANE  RecordType = "DANE"
+	RecordTypeTLSA  RecordType = "TLSA"
+	RecordTypeDS    RecordType = "DS"
+	RecordTypeCAA   RecordType = "CAA"
+)
+
+var recordTypes = map[RecordType]bool{
+	RecordTypeA:     true,
+	RecordTypeAAAA:  true,
+	RecordTypeNS:    true,
+	RecordTypeMX:    true,
+	RecordTypeCNAME: true,
+	RecordTypeRP:    true,
+	RecordTypeTXT:   true,
+	RecordTypeSOA:   true,
+	RecordTypeHINFO: true,
+	RecordTypeSRV:   true,
+	RecordTypeDANE:  true,
+	RecordTypeTLSA:  true,
+	RecordTypeDS:    true,
+	RecordTypeCAA:   true,
+}
+
+// IsValid returns whether t is a record type supported by the API.
+func (t RecordType) IsValid() bool {
+	return recordTypes[t]
+}
+
+// InvalidRecordTypeError is returned when a Record of a type not supported
+// by the API is to be created or updated. It matches ErrInvalidInput when
+// used with errors.Is.
+type InvalidRecordTypeError struct {
+	Type RecordType
+}
+
+func (e *InvalidRecordTypeError) Error() string {
+	return fmt.Sprintf("hdns: invalid record type %q", string(e.Type))
+}
+
+// Is reports whether target is ErrInvalidInput.
+func (e *InvalidRecordTypeError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+// validateRecordType returns an *InvalidRecordTypeError if t is not
+// supported by the API.
+func validateRecordType(t RecordType) error {
+	if !t.IsValid() {
+		return &InvalidRecordTypeError{Type: t}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package hdns
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRecordClientRejectsInvalidRecordType(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	var calls int32
+	env.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	})
+
+	const invalid RecordType = "SPF"
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name string
+		call func() error
+	}{
+		{"Create", func() error {
+			_, _, err := env.Client.Record.Create(ctx, RecordCreateOpts{Name: "www", Type: invalid, ZoneID: "1"})
+			return err
+		}},
+		{"Update", func() error {
+			_, _, err := env.Client.Record.Update(ctx, "1", RecordUpdateOpts{Name: "www", Type: invalid, ZoneID: "1"})
+			return err
+		}},
+		{"BulkCreate", func() error {
+			_, _, err := env.Client.Record.BulkCreate(ctx, RecordBulkCreateOpts{Records: []RecordCreateOpts{
+				{Name: "www", Type: RecordTypeA, ZoneID: "1"},
+				{Name: "www", Type: invalid, ZoneID: "1"},
+			}})
+			return err
+		}},
+		{"BulkUpdate", func() error {
+			_, _, err := env.Client.Record.BulkUpdate(ctx, RecordBulkUpdateOpts{Records: []RecordBulkUpdateEntry{
+				{ID: "1", RecordUpdateOpts: RecordUpdateOpts{Name: "www", Type: RecordTypeA, ZoneID: "1"}},
+				{ID: "2", RecordUpdateOpts: RecordUpdateOpts{Name: "www", Type: invalid, ZoneID: "1"}},
+			}})
+			return err
+		}},
+	} {
+		err := tt.call()
+		var typeErr *InvalidRecordTypeError
+		if !errors.As(err, &typeErr) || typeErr.Type != invalid {
+			t.Errorf("%s: expected *InvalidRecordTypeError for %s, got %v", tt.name, invalid, err)
+		}
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%s: expected error to match ErrInvalidInput, got %v", tt.name, err)
+		}
+	}
+	if n := atomic.LoadInt32(&calls); n != 0 {
+		t.Errorf("expected no API calls, got %d", n)
+	}
+}
+
+func TestRecordTypeIsValid(t *testing.T) {
+	for _, typ := range []RecordType{RecordTypeA, RecordTypeCAA, RecordTypeTLSA} {
+		if !typ.IsValid() {
+			t.Errorf("expected %s to be valid", typ)
+		}
+	}
+	for _, typ := range []RecordType{"", "a", "SPF"} {
+		if typ.IsValid() {
+			t.Errorf("expected %q to be invalid", typ)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package hdns
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// A RetryPolicy decides whether a failed request is retried.
+type RetryPolicy interface {
+	// Retry reports whether a request with the given method is retried.
+	// The statusCode argument is the status code of the response, or 0
+	// if no complete response was received, err is the error the request failed
+	// with and retries is the number of retries already performed.
+	Retry(method string, statusCode int, err error, retries int) bool
+}
+
+// The RetryPolicyFunc type is an adapter to allow the use of ordinary
+// functions as RetryPolicy.
+type RetryPolicyFunc func(method string, statusCode int, err error, retries int) bool
+
+// Retry calls f(method, statusCode, err, retries).
+func (f RetryPolicyFunc) Retry(method string, statusCode int, err error, retries int) bool {
+	return f(method, statusCode, err, retries)
+}
+
+// DefaultRetryPolicy is the RetryPolicy used by a Client unless configured
+// otherwise. It retries idempotent requests (GET, HEAD, PUT and DELETE)
+// failing with a transient network error or with the status codes 502, 503
+// and 504. Other requests, such as POST, are never retried.
+var DefaultRetryPolicy RetryPolicy = RetryPolicyFunc(defaultRetry)
+
+func defaultRetry(method string, statusCode int, err error, _ int) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+
+	switch statusCode {
+	case 0:
+		return isTransientError(err)
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// isTransientError returns whether err is a network error which may not
+// occur again when the request is retried.
+func isTransientError(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
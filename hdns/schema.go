@@ -5,7 +5,7 @@ import (
 )
 
 // This file provides converter functions to convert models in the
-// schema package to models in the hdns package.
+// schema package to models in the hdns package and vice versa.
 
 // ZoneFromSchema converts a schema.Zone to a Zone.
 func ZoneFromSchema(s schema.Zone) *Zone {
@@ -24,28 +24,63 @@ func ZoneFromSchema(s schema.Zone) *Zone {
 		Permission:     s.Permission,
 		Project:        s.Project,
 		RecordsCount:   s.RecordsCount,
+		Registrar:      s.Registrar,
 		Status:         s.Status,
 		TXTVerification: ZoneTxtVerification{
 			Name:  s.TXTVerification.Name,
 			Token: s.TXTVerification.Token,
 		},
 		Verified: s.Verified,
+		Extra:    s.Extra,
+		Raw:      s.Raw,
 	}
 	for _, ns := range s.LegacyNS {
 		zone.LegacyNS = append(zone.LegacyNS, ns)
 	}
 	for _, ns := range s.NS {
-		zone.NS = append(zone.LegacyNS, ns)
+		zone.NS = append(zone.NS, ns)
 	}
 	return zone
 }
 
+// ZoneToSchema converts a Zone to a schema.Zone.
+func ZoneToSchema(zone Zone) schema.Zone {
+	s := schema.Zone{
+		ID:             zone.ID,
+		Name:           zone.Name,
+		TTL:            zone.TTL,
+		Created:        zone.Created,
+		IsSecondaryDNS: zone.IsSecondaryDNS,
+		LegacyDNSHost:  zone.LegacyDNSHost,
+		LegacyNS:       []string{},
+		Modified:       zone.Modified,
+		NS:             []string{},
+		Owner:          zone.Owner,
+		Paused:         zone.Paused,
+		Permission:     zone.Permission,
+		Project:        zone.Project,
+		RecordsCount:   zone.RecordsCount,
+		Registrar:      zone.Registrar,
+		Status:         zone.Status,
+		TXTVerification: schema.ZoneTxtVerification{
+			Name:  zone.TXTVerification.Name,
+			Token: zone.TXTVerification.Token,
+		},
+		Verified: zone.Verified,
+		Extra:    zone.Extra,
+		Raw:      zone.Raw,
+	}
+	s.LegacyNS = append(s.LegacyNS, zone.LegacyNS...)
+	s.NS = append(s.NS, zone.NS...)
+	return s
+}
+
 // BaseRecordFromSchema converts a schema.BaseRecord to a BaseRecord.
 func BaseRecordFromSchema(s schema.BaseRecord) *BaseRecord {
 	return &BaseRecord{
 		Name:   s.Name,
 		TTL:    s.TTL,
-		Type:   s.Type,
+		Type:   RecordType(s.Type),
 		Value:  s.Value,
 		ZoneID: s.ZoneID,
 	}
@@ -58,6 +93,31 @@ func RecordFromSchema(s schema.Record) *Record {
 		ID:         s.ID,
 		Created:    s.Created,
 		Modified:   s.Modified,
+		Extra:      s.Extra,
+		Raw:        s.Raw,
+	}
+}
+
+// BaseRecordToSchema converts a BaseRecord to a schema.BaseRecord.
+func BaseRecordToSchema(record BaseRecord) schema.BaseRecord {
+	return schema.BaseRecord{
+		Name:   record.Name,
+		TTL:    record.TTL,
+		Type:   string(record.Type),
+		Value:  record.Value,
+		ZoneID: record.ZoneID,
+	}
+}
+
+// RecordToSchema converts a Record to a schema.Record.
+func RecordToSchema(record Record) schema.Record {
+	return schema.Record{
+		BaseRecord: BaseRecordToSchema(record.BaseRecord),
+		ID:         record.ID,
+		Created:    record.Created,
+		Modified:   record.Modified,
+		Extra:      record.Extra,
+		Raw:        record.Raw,
 	}
 }
 
@@ -77,6 +137,38 @@ func RecordsFromSchema(s []schema.Record) []*Record {
 	return records
 }
 
+// PrimaryServerFromSchema converts a schema.PrimaryServer to a PrimaryServer.
+func PrimaryServerFromSchema(s schema.PrimaryServer) *PrimaryServer {
+	return &PrimaryServer{
+		ID:       s.ID,
+		Address:  s.Address,
+		Port:     s.Port,
+		ZoneID:   s.ZoneID,
+		Created:  s.Created,
+		Modified: s.Modified,
+	}
+}
+
+// PrimaryServerToSchema converts a PrimaryServer to a schema.PrimaryServer.
+func PrimaryServerToSchema(primaryServer PrimaryServer) schema.PrimaryServer {
+	return schema.PrimaryServer{
+		ID:       primaryServer.ID,
+		Address:  primaryServer.Address,
+		Port:     primaryServer.Port,
+		ZoneID:   primaryServer.ZoneID,
+		Created:  primaryServer.Created,
+		Modified: primaryServer.Modified,
+	}
+}
+
+func PrimaryServersFromSchema(s []schema.PrimaryServer) []*PrimaryServer {
+	var primaryServers []*PrimaryServer
+	for _, ps := range s {
+		primaryServers = append(primaryServers, PrimaryServerFromSchema(ps))
+	}
+	return primaryServers
+}
+
 // PaginationFromSchema converts a schema.MetaPagination to a Pagination.
 func PaginationFromSchema(s schema.MetaPagination) Pagination {
 	return Pagination{
@@ -97,3 +189,16 @@ func ErrorFromSchema(s schema.Error) Error {
 	}
 	return e
 }
+
+// ValidationErrorFromSchema converts the details of a schema.Error with
+// code 422 to a ValidationError wrapping apiErr.
+func ValidationErrorFromSchema(s schema.ErrorDetailsInvalidInput, apiErr Error) *ValidationError {
+	e := &ValidationError{Err: apiErr}
+	for _, f := range s.Fields {
+		e.Fields = append(e.Fields, FieldError{
+			Field:    f.Name,
+			Messages: f.Messages,
+		})
+	}
+	return e
+}
@@ -4,17 +4,27 @@ import "encoding/json"
 
 // Error represents the schema of an error response.
 type Error struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code       int             `json:"code"`
+	Message    string          `json:"message"`
+	DetailsRaw json.RawMessage `json:"details"`
+	Details    interface{}     `json:"-"`
 }
 
-// UnmarshalJSON overrides default json unmarshalling.
+// UnmarshalJSON overrides default json unmarshalling. Details are decoded
+// on a best-effort basis: if they don't match the expected schema, Details
+// is left nil and only DetailsRaw is set.
 func (e *Error) UnmarshalJSON(data []byte) (err error) {
 	type Alias Error
 	alias := (*Alias)(e)
 	if err = json.Unmarshal(data, alias); err != nil {
 		return
 	}
+	if e.Code == 422 && len(e.DetailsRaw) > 0 {
+		details := ErrorDetailsInvalidInput{}
+		if json.Unmarshal(e.DetailsRaw, &details) == nil {
+			alias.Details = details
+		}
+	}
 	return
 }
 
@@ -22,3 +32,12 @@ func (e *Error) UnmarshalJSON(data []byte) (err error) {
 type ErrorResponse struct {
 	Error Error `json:"error"`
 }
+
+// ErrorDetailsInvalidInput defines the schema of the Details field
+// of an error with code 422.
+type ErrorDetailsInvalidInput struct {
+	Fields []struct {
+		Name     string   `json:"name"`
+		Messages []string `json:"messages"`
+	} `json:"fields"`
+}
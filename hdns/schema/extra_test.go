@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestZoneUnknownFields(t *testing.T) {
+	data := []byte(`{"id":"1","name":"example.com","ttl":86400,"txt_verification":{"name":"n","token":"t"},"dnssec":{"enabled":true},"tags":["a"]}`)
+
+	var zone Zone
+	if err := json.Unmarshal(data, &zone); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]json.RawMessage{
+		"dnssec": json.RawMessage(`{"enabled":true}`),
+		"tags":   json.RawMessage(`["a"]`),
+	}
+	if !reflect.DeepEqual(zone.Extra, expected) {
+		t.Errorf("expected extra %s, got %s", expected, zone.Extra)
+	}
+	if string(zone.Raw) != string(data) {
+		t.Errorf("expected raw %s, got %s", data, zone.Raw)
+	}
+	if zone.ID != "1" || zone.TTL != 86400 || zone.TXTVerification.Token != "t" {
+		t.Errorf("unexpected zone: %+v", zone)
+	}
+
+	encoded, err := json.Marshal(zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if string(fields["dnssec"]) != `{"enabled":true}` || string(fields["id"]) != `"1"` {
+		t.Errorf("unknown fields not encoded: %s", encoded)
+	}
+}
+
+func TestRecordUnknownFields(t *testing.T) {
+	data := []byte(`{"id":"2","name":"www","ttl":60,"type":"A","value":"192.0.2.1","zone_id":"1","created":"","modified":"","comment":"web server"}`)
+
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatal(err)
+	}
+	// The fields of the embedded BaseRecord are known.
+	expected := map[string]json.RawMessage{"comment": json.RawMessage(`"web server"`)}
+	if !reflect.DeepEqual(record.Extra, expected) {
+		t.Errorf("expected extra %s, got %s", expected, record.Extra)
+	}
+	if string(record.Raw) != string(data) {
+		t.Errorf("expected raw %s, got %s", data, record.Raw)
+	}
+	if record.ID != "2" || record.Name != "www" || record.ZoneID != "1" {
+		t.Errorf("unexpected record: %+v", record)
+	}
+}
+
+func TestRecordWithoutUnknownFields(t *testing.T) {
+	var record Record
+	if err := json.Unmarshal([]byte(`{"id":"2","name":"www","type":"A"}`), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Extra != nil {
+		t.Errorf("expected no extra fields, got %s", record.Extra)
+	}
+}
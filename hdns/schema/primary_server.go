@@ -0,0 +1,38 @@
+package schema
+
+type PrimaryServer struct {
+	ID       string `json:"id"`
+	Address  string `json:"address"`
+	Port     int    `json:"port"`
+	ZoneID   string `json:"zone_id"`
+	Created  Time   `json:"created"`
+	Modified Time   `json:"modified"`
+}
+
+type PrimaryServerGetResponse struct {
+	PrimaryServer PrimaryServer `json:"primary_server"`
+}
+
+type PrimaryServerListResponse struct {
+	PrimaryServers []PrimaryServer `json:"primary_servers"`
+}
+
+type PrimaryServerCreateRequest struct {
+	Address string `json:"address"`
+	Port    int    `json:"port"`
+	ZoneID  string `json:"zone_id"`
+}
+
+type PrimaryServerCreateResponse struct {
+	PrimaryServer PrimaryServer `json:"primary_server"`
+}
+
+type PrimaryServerUpdateRequest struct {
+	Address string `json:"address"`
+	Port    int    `json:"port"`
+	ZoneID  string `json:"zone_id"`
+}
+
+type PrimaryServerUpdateResponse struct {
+	PrimaryServer PrimaryServer `json:"primary_server"`
+}
@@ -1,5 +1,7 @@
 package schema
 
+import "encoding/json"
+
 type BaseRecord struct {
 	Name   string `json:"name"`
 	TTL    int    `json:"ttl"`
@@ -13,6 +15,34 @@ type Record struct {
 	ID       string `json:"id"`
 	Created  Time   `json:"created"`
 	Modified Time   `json:"modified"`
+
+	// Extra holds the members of the JSON object which are not known yet.
+	Extra map[string]json.RawMessage `json:"-"`
+	// Raw holds the JSON object the Record was decoded from.
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON overrides default json unmarshalling to preserve unknown
+// members of the JSON object.
+func (r *Record) UnmarshalJSON(data []byte) error {
+	type Alias Record
+	if err := json.Unmarshal(data, (*Alias)(r)); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, r)
+	if err != nil {
+		return err
+	}
+	r.Extra = extra
+	r.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON overrides default json marshalling to include the unknown
+// members of the JSON object the Record was decoded from.
+func (r Record) MarshalJSON() ([]byte, error) {
+	type Alias Record
+	return marshalWithExtra(Alias(r), r.Extra)
 }
 
 type RecordCreateRequest struct {
@@ -53,8 +83,17 @@ type RecordBulkCreateResponse struct {
 	ValidRecords   []BaseRecord `json:"valid_records"`
 }
 
+type RecordBulkUpdateRecord struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	TTL    int    `json:"ttl"`
+	Type   string `json:"type"`
+	Value  string `json:"value"`
+	ZoneID string `json:"zone_id"`
+}
+
 type RecordBulkUpdateRequest struct {
-	Records []RecordUpdateRequest `json:"records"`
+	Records []RecordBulkUpdateRecord `json:"records"`
 }
 
 type RecordBulkUpdateResponse struct {
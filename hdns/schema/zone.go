@@ -28,6 +28,36 @@ func (zt *Time) UnmarshalJSON(data []byte) error {
 	return err
 }
 
+// MarshalJSON encodes the time in RFC 3339 format, which is understood by
+// UnmarshalJSON. The zero time is encoded as an empty string.
+func (zt Time) MarshalJSON() ([]byte, error) {
+	if zt.IsZero() {
+		return []byte(`""`), nil
+	}
+	return zt.Time.MarshalJSON()
+}
+
+// MarshalYAML encodes the time like MarshalJSON does.
+func (zt Time) MarshalYAML() (interface{}, error) {
+	if zt.IsZero() {
+		return "", nil
+	}
+	return zt.Format(time.RFC3339Nano), nil
+}
+
+// UnmarshalYAML decodes a time encoded by MarshalYAML.
+func (zt *Time) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return zt.UnmarshalJSON(data)
+}
+
 type ZoneTxtVerification struct {
 	Name  string `json:"name"`
 	Token string `json:"token"`
@@ -52,6 +82,34 @@ type Zone struct {
 	Status          string              `json:"status"`
 	TXTVerification ZoneTxtVerification `json:"txt_verification"`
 	Verified        Time                `json:"verified"`
+
+	// Extra holds the members of the JSON object which are not known yet.
+	Extra map[string]json.RawMessage `json:"-"`
+	// Raw holds the JSON object the Zone was decoded from.
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON overrides default json unmarshalling to preserve unknown
+// members of the JSON object.
+func (z *Zone) UnmarshalJSON(data []byte) error {
+	type Alias Zone
+	if err := json.Unmarshal(data, (*Alias)(z)); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, z)
+	if err != nil {
+		return err
+	}
+	z.Extra = extra
+	z.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON overrides default json marshalling to include the unknown
+// members of the JSON object the Zone was decoded from.
+func (z Zone) MarshalJSON() ([]byte, error) {
+	type Alias Zone
+	return marshalWithExtra(Alias(z), z.Extra)
 }
 
 type ZoneCreateResponse struct {
@@ -66,6 +124,15 @@ type ZoneUpdateResponse struct {
 	Zone Zone `json:"zone"`
 }
 
+type ZoneImportResponse struct {
+	Zone Zone `json:"zone"`
+}
+
+type ZoneFileValidateResponse struct {
+	ParsedRecords int          `json:"parsed_records"`
+	ValidRecords  []BaseRecord `json:"valid_records"`
+}
+
 type ZoneCreateRequest struct {
 	Name string `json:"name"`
 	TTL  int    `json:"ttl"`
@@ -76,6 +143,10 @@ type ZoneUpdateRequest struct {
 	TTL  int    `json:"ttl"`
 }
 
+type ZoneListResponse struct {
+	Zones []Zone `json:"zones"`
+}
+
 type ZoneAllResponse struct {
 	Zones []Zone `json:"zones"`
 }
@@ -0,0 +1,95 @@
+package hdns
+
+import (
+	"context"
+	"strings"
+)
+
+// A Tracer traces the operations of ZoneClient and RecordClient, such as
+// ZoneClient.Create.
+type Tracer interface {
+	// StartOperation starts tracing the operation with the given name,
+	// e.g. "RecordClient.Create". The returned context is used for the
+	// API calls made by the operation.
+	StartOperation(ctx context.Context, name string, attrs OperationAttributes) (context.Context, OperationSpan)
+}
+
+// OperationAttributes describes the resources an operation is about, as
+// far as they are known when the operation starts. For bulk operations,
+// ZoneID and RecordType hold the distinct values of all records joined by
+// commas, RecordID is left empty and RecordCount is set instead.
+type OperationAttributes struct {
+	ZoneID      string
+	ZoneName    string
+	RecordID    string
+	RecordType  string
+	RecordCount int
+}
+
+// An OperationSpan traces a single operation.
+type OperationSpan interface {
+	// ObserveCall is called after every API call made by the operation,
+	// including the calls of nested operations, with its response, which is
+	// nil if none was received, the number of retries performed and the
+	// error of the call.
+	ObserveCall(resp *Response, retries int, err error)
+	// End is called with the error of the operation when it ends.
+	End(err error)
+}
+
+// bulkOperationAttributes returns the OperationAttributes of a bulk
+// operation on count records with the given zone IDs and types.
+func bulkOperationAttributes(zoneIDs []string, types []RecordType, count int) OperationAttributes {
+	typeNames := make([]string, 0, len(types))
+	for _, t := range types {
+		typeNames = append(typeNames, string(t))
+	}
+	return OperationAttributes{
+		ZoneID:      joinDistinct(zoneIDs),
+		RecordType:  joinDistinct(typeNames),
+		RecordCount: count,
+	}
+}
+
+// joinDistinct joins the distinct non-empty values in the order they first
+// occur.
+func joinDistinct(values []string) string {
+	var distinct []string
+	seen := map[string]bool{}
+	for _, v := range values {
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		distinct = append(distinct, v)
+	}
+	return strings.Join(distinct, ",")
+}
+
+type operationSpansKey struct{}
+
+// startOperation starts tracing an operation, if a Tracer is configured. The
+// returned function must be called with the error of the operation when it
+// ends.
+func (c *Client) startOperation(ctx context.Context, name string, attrs OperationAttributes) (context.Context, func(error)) {
+	if c.tracer == nil {
+		return ctx, func(error) {}
+	}
+	ctx, span := c.tracer.StartOperation(ctx, name, attrs)
+	// Keep the spans of enclosing operations, so they observe the API
+	// calls of nested operations as well.
+	parents, _ := ctx.Value(operationSpansKey{}).([]OperationSpan)
+	spans := make([]OperationSpan, 0, len(parents)+1)
+	spans = append(spans, parents...)
+	spans = append(spans, span)
+	return context.WithValue(ctx, operationSpansKey{}, spans), span.End
+}
+
+// observeCall reports an API call to the spans of the operation it was made
+// by and of all operations enclosing it.
+func observeCall(ctx context.Context, resp *Response, retries int, err error) {
+	spans, _ := ctx.Value(operationSpansKey{}).([]OperationSpan)
+	for _, span := range spans {
+		span.ObserveCall(resp, retries, err)
+	}
+}
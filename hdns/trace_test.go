@@ -0,0 +1,76 @@
+package hdns
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+type testSpan struct {
+	tracer *testTracer
+	name   string
+	attrs  OperationAttributes
+	calls  int
+	ended  bool
+}
+
+func (t *testTracer) StartOperation(ctx context.Context, name string, attrs OperationAttributes) (context.Context, OperationSpan) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := &testSpan{tracer: t, name: name, attrs: attrs}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func (s *testSpan) ObserveCall(*Response, int, error) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.calls++
+}
+
+func (s *testSpan) End(error) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.ended = true
+}
+
+func TestTracerBulkDelete(t *testing.T) {
+	tracer := &testTracer{}
+	env := newTestEnv(WithTracer(tracer))
+	defer env.Teardown()
+
+	env.Mux.HandleFunc("/records/", func(w http.ResponseWriter, r *http.Request) {})
+
+	if _, err := env.Client.Record.BulkDelete(context.Background(), []string{"1", "2", "3"}, RecordBulkDeleteOpts{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	if len(tracer.spans) != 4 {
+		t.Fatalf("expected 4 spans, got %d", len(tracer.spans))
+	}
+	bulk := tracer.spans[0]
+	if bulk.name != "RecordClient.BulkDelete" {
+		t.Fatalf("unexpected first span: %s", bulk.name)
+	}
+	if bulk.attrs != (OperationAttributes{RecordCount: 3}) {
+		t.Errorf("unexpected attributes: %+v", bulk.attrs)
+	}
+	// The calls of the nested Delete operations are observed by the span
+	// of the bulk operation as well.
+	if bulk.calls != 3 || !bulk.ended {
+		t.Errorf("expected 3 observed calls and an ended span, got %d calls, ended %v", bulk.calls, bulk.ended)
+	}
+	for _, span := range tracer.spans[1:] {
+		if span.name != "RecordClient.Delete" || span.attrs.RecordID == "" || span.calls != 1 {
+			t.Errorf("unexpected nested span: %+v", span)
+		}
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/alxrem/hdns-go/hdns/schema"
+	"io"
+	"net/url"
+	"strings"
 )
 
 type ZoneTxtVerification struct {
@@ -13,6 +16,8 @@ type ZoneTxtVerification struct {
 	Token string
 }
 
+// Zone represents a DNS zone. Zone implements json.Marshaler, so a struct
+// embedding a Zone is encoded as the Zone alone; use a named field instead.
 type Zone struct {
 	ID              string
 	Name            string
@@ -32,13 +37,22 @@ type Zone struct {
 	Status          string
 	TXTVerification ZoneTxtVerification
 	Verified        schema.Time
+
+	// Extra holds the fields returned from the API which are not known
+	// to this library yet.
+	Extra map[string]json.RawMessage
+	// Raw holds the JSON object the Zone was decoded from, if any.
+	Raw json.RawMessage
 }
 
 type ZoneClient struct {
 	client *Client
 }
 
-func (c *ZoneClient) GetByID(ctx context.Context, id string) (*Zone, *Response, error) {
+func (c *ZoneClient) GetByID(ctx context.Context, id string) (_ *Zone, _ *Response, err error) {
+	ctx, end := c.client.startOperation(ctx, "ZoneClient.GetByID", OperationAttributes{ZoneID: id})
+	defer func() { end(err) }()
+
 	req, err := c.client.NewRequest(ctx, "GET", fmt.Sprintf("/zones/%s", id), nil)
 	if err != nil {
 		return nil, nil, err
@@ -55,24 +69,121 @@ func (c *ZoneClient) GetByID(ctx context.Context, id string) (*Zone, *Response,
 	return ZoneFromSchema(body.Zone), resp, nil
 }
 
-func (c *ZoneClient) All(ctx context.Context) ([]*Zone, error) {
-	req, err := c.client.NewRequest(ctx, "GET", "/zones", nil)
+// GetByName retrieves a Zone by its name. The name is matched
+// case-insensitively and a trailing dot is ignored. If the zone does not
+// exist, nil is returned.
+func (c *ZoneClient) GetByName(ctx context.Context, name string) (_ *Zone, _ *Response, err error) {
+	ctx, end := c.client.startOperation(ctx, "ZoneClient.GetByName", OperationAttributes{ZoneName: name})
+	defer func() { end(err) }()
+
+	name = normalizeZoneName(name)
+	if name == "" {
+		return nil, nil, nil
+	}
+	zones, resp, err := c.List(ctx, ZoneListOpts{Name: name})
 	if err != nil {
-		return nil, err
+		if IsError(err, ErrorCodeNotFound) {
+			return nil, resp, nil
+		}
+		return nil, resp, err
+	}
+	for _, zone := range zones {
+		if normalizeZoneName(zone.Name) == name {
+			return zone, resp, nil
+		}
+	}
+	return nil, resp, nil
+}
+
+// Get retrieves a Zone by its ID or, if no zone with that ID exists, by its
+// name. If the zone does not exist, nil is returned.
+func (c *ZoneClient) Get(ctx context.Context, idOrName string) (_ *Zone, _ *Response, err error) {
+	attrs := OperationAttributes{ZoneID: idOrName}
+	if strings.Contains(idOrName, ".") {
+		// Zone IDs never contain dots, so it's a name.
+		attrs = OperationAttributes{ZoneName: idOrName}
+	}
+	ctx, end := c.client.startOperation(ctx, "ZoneClient.Get", attrs)
+	defer func() { end(err) }()
+
+	zone, resp, err := c.GetByID(ctx, idOrName)
+	if zone != nil || err != nil {
+		return zone, resp, err
+	}
+	return c.GetByName(ctx, idOrName)
+}
+
+func normalizeZoneName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// ZoneListOpts specifies options for listing zones.
+type ZoneListOpts struct {
+	ListOpts
+	Name       string
+	SearchName string
+}
+
+func (l ZoneListOpts) values() url.Values {
+	vals := l.ListOpts.values()
+	if l.Name != "" {
+		vals.Add("name", l.Name)
+	}
+	if l.SearchName != "" {
+		vals.Add("search_name", l.SearchName)
 	}
+	return vals
+}
+
+// List returns a list of zones for a specific page.
+func (c *ZoneClient) List(ctx context.Context, opts ZoneListOpts) (_ []*Zone, _ *Response, err error) {
+	ctx, end := c.client.startOperation(ctx, "ZoneClient.List", OperationAttributes{ZoneName: opts.Name})
+	defer func() { end(err) }()
 
-	var body schema.ZoneAllResponse
-	_, err = c.client.Do(req, &body)
+	path := "/zones?" + opts.values().Encode()
+	req, err := c.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	var body schema.ZoneListResponse
+	resp, err := c.client.Do(req, &body)
+	if err != nil {
+		return nil, resp, err
+	}
 	zones := make([]*Zone, 0, len(body.Zones))
 	for _, z := range body.Zones {
 		zones = append(zones, ZoneFromSchema(z))
 	}
+	return zones, resp, nil
+}
+
+// All returns all zones.
+func (c *ZoneClient) All(ctx context.Context) ([]*Zone, error) {
+	return c.AllWithOpts(ctx, ZoneListOpts{ListOpts: ListOpts{PerPage: 100}})
+}
+
+// AllWithOpts returns all zones matching the given options.
+func (c *ZoneClient) AllWithOpts(ctx context.Context, opts ZoneListOpts) (_ []*Zone, err error) {
+	ctx, end := c.client.startOperation(ctx, "ZoneClient.AllWithOpts", OperationAttributes{ZoneName: opts.Name})
+	defer func() { end(err) }()
+
+	allZones := []*Zone{}
+
+	_, err = c.client.all(func(page int) (*Response, error) {
+		opts.Page = page
+		zones, resp, err := c.List(ctx, opts)
+		if err != nil {
+			return resp, err
+		}
+		allZones = append(allZones, zones...)
+		return resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	return zones, nil
+	return allZones, nil
 }
 
 // ZoneCreateOpts specifies parameters for creating a Zone.
@@ -82,7 +193,10 @@ type ZoneCreateOpts struct {
 }
 
 // Create creates a Zone.
-func (c *ZoneClient) Create(ctx context.Context, opts ZoneCreateOpts) (*Zone, *Response, error) {
+func (c *ZoneClient) Create(ctx context.Context, opts ZoneCreateOpts) (_ *Zone, _ *Response, err error) {
+	ctx, end := c.client.startOperation(ctx, "ZoneClient.Create", OperationAttributes{ZoneName: opts.Name})
+	defer func() { end(err) }()
+
 	reqBody := schema.ZoneCreateRequest{
 		Name: opts.Name,
 		TTL:  opts.TTL,
@@ -106,7 +220,10 @@ func (c *ZoneClient) Create(ctx context.Context, opts ZoneCreateOpts) (*Zone, *R
 	return ZoneFromSchema(respBody.Zone), resp, nil
 }
 
-func (c *ZoneClient) Delete(ctx context.Context, id string) (*Response, error) {
+func (c *ZoneClient) Delete(ctx context.Context, id string) (_ *Response, err error) {
+	ctx, end := c.client.startOperation(ctx, "ZoneClient.Delete", OperationAttributes{ZoneID: id})
+	defer func() { end(err) }()
+
 	req, err := c.client.NewRequest(ctx, "DELETE", fmt.Sprintf("/zones/%s", id), nil)
 	if err != nil {
 		return nil, err
@@ -121,7 +238,10 @@ type ZoneUpdateOpts struct {
 }
 
 // Update updates a Zone.
-func (c *ZoneClient) Update(ctx context.Context, id string, opts ZoneUpdateOpts) (*Zone, *Response, error) {
+func (c *ZoneClient) Update(ctx context.Context, id string, opts ZoneUpdateOpts) (_ *Zone, _ *Response, err error) {
+	ctx, end := c.client.startOperation(ctx, "ZoneClient.Update", OperationAttributes{ZoneID: id, ZoneName: opts.Name})
+	defer func() { end(err) }()
+
 	reqBody := schema.ZoneUpdateRequest{
 		Name: opts.Name,
 		TTL:  opts.TTL,
@@ -145,3 +265,70 @@ func (c *ZoneClient) Update(ctx context.Context, id string, opts ZoneUpdateOpts)
 
 	return ZoneFromSchema(respBody.Zone), resp, nil
 }
+
+// ImportZoneFile imports a zone file in BIND format into the existing Zone
+// with the given ID. The records of the zone are replaced by the ones found
+// in the zone file.
+func (c *ZoneClient) ImportZoneFile(ctx context.Context, id string, zoneFile io.Reader) (_ *Zone, _ *Response, err error) {
+	ctx, end := c.client.startOperation(ctx, "ZoneClient.ImportZoneFile", OperationAttributes{ZoneID: id})
+	defer func() { end(err) }()
+
+	path := fmt.Sprintf("/zones/%s/import", id)
+	req, err := c.client.NewRequestWithContentType(ctx, "POST", path, "text/plain", zoneFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var respBody schema.ZoneImportResponse
+	resp, err := c.client.Do(req, &respBody)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return ZoneFromSchema(respBody.Zone), resp, nil
+}
+
+// ExportZoneFile writes the Zone with the given ID in BIND zone file format
+// to w.
+func (c *ZoneClient) ExportZoneFile(ctx context.Context, id string, w io.Writer) (_ *Response, err error) {
+	ctx, end := c.client.startOperation(ctx, "ZoneClient.ExportZoneFile", OperationAttributes{ZoneID: id})
+	defer func() { end(err) }()
+
+	req, err := c.client.NewRequest(ctx, "GET", fmt.Sprintf("/zones/%s/export", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.client.Do(req, w)
+}
+
+// ZoneFileValidateResult is the result of validating a zone file.
+type ZoneFileValidateResult struct {
+	ParsedRecords int
+	ValidRecords  []*BaseRecord
+}
+
+// ValidateZoneFile validates a zone file in BIND format without importing it.
+// If the API rejects the zone file, an error matching ErrInvalidInput is
+// returned.
+func (c *ZoneClient) ValidateZoneFile(ctx context.Context, zoneFile io.Reader) (_ ZoneFileValidateResult, _ *Response, err error) {
+	ctx, end := c.client.startOperation(ctx, "ZoneClient.ValidateZoneFile", OperationAttributes{})
+	defer func() { end(err) }()
+
+	req, err := c.client.NewRequestWithContentType(ctx, "POST", "/zones/file/validate", "text/plain", zoneFile)
+	if err != nil {
+		return ZoneFileValidateResult{}, nil, err
+	}
+
+	var respBody schema.ZoneFileValidateResponse
+	resp, err := c.client.Do(req, &respBody)
+	if err != nil {
+		return ZoneFileValidateResult{}, resp, err
+	}
+
+	result := ZoneFileValidateResult{
+		ParsedRecords: respBody.ParsedRecords,
+		ValidRecords:  BaseRecordsFromSchema(respBody.ValidRecords),
+	}
+
+	return result, resp, nil
+}